@@ -52,6 +52,34 @@ func (b *Bot) Raw(method string, payload interface{}) ([]byte, error) {
 	return data, extractOk(data)
 }
 
+// Call calls the Bot API method with the given payload and decodes
+// its result into the value pointed to by result, which may be nil
+// to discard it. If the method returns true instead of the expected
+// object, Call returns ErrTrueResult unless result is a *bool.
+//
+// Example:
+//
+//	var link tele.ChatInviteLink
+//	err := b.Call("exportChatInviteLink", params, &link)
+func (b *Bot) Call(method string, payload, result interface{}) error {
+	data, err := b.Raw(method, payload)
+	if err != nil {
+		return err
+	}
+	return extractResult(data, result)
+}
+
+// CallFiles is the same as Call, but uploads the files along with
+// the params. Files already in the cloud or given by URL are passed
+// as the regular params.
+func (b *Bot) CallFiles(method string, params map[string]string, files map[string]File, result interface{}) error {
+	data, err := b.sendFiles(method, files, params)
+	if err != nil {
+		return err
+	}
+	return extractResult(data, result)
+}
+
 func (b *Bot) sendFiles(method string, files map[string]File, params map[string]string) ([]byte, error) {
 	rawFiles := make(map[string]interface{})
 	for name, f := range files {
@@ -194,18 +222,11 @@ func (b *Bot) sendMedia(media Media, params map[string]string, files map[string]
 }
 
 func (b *Bot) getMe() (*User, error) {
-	data, err := b.Raw("getMe", nil)
-	if err != nil {
+	var me *User
+	if err := b.Call("getMe", nil, &me); err != nil {
 		return nil, err
 	}
-
-	var resp struct {
-		Result *User
-	}
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, wrapError(err)
-	}
-	return resp.Result, nil
+	return me, nil
 }
 
 func (b *Bot) getUpdates(offset, limit int, timeout time.Duration, allowed []string) ([]Update, error) {
@@ -221,18 +242,11 @@ func (b *Bot) getUpdates(offset, limit int, timeout time.Duration, allowed []str
 		params["limit"] = strconv.Itoa(limit)
 	}
 
-	data, err := b.Raw("getUpdates", params)
-	if err != nil {
+	var updates []Update
+	if err := b.Call("getUpdates", params, &updates); err != nil {
 		return nil, err
 	}
-
-	var resp struct {
-		Result []Update
-	}
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, wrapError(err)
-	}
-	return resp.Result, nil
+	return updates, nil
 }
 
 // extractOk checks given result for error. If result is ok returns nil.
@@ -287,6 +301,34 @@ func extractOk(data []byte) error {
 	return err
 }
 
+// extractResult decodes the result field of the given data into result.
+// Should be called after extractOk or b.Raw() to handle possible errors.
+func extractResult(data []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+
+	var resp struct {
+		Result json.RawMessage
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return wrapError(err)
+	}
+
+	if bytes.Equal(resp.Result, []byte("true")) {
+		switch result.(type) {
+		case *bool, *interface{}, *json.RawMessage:
+		default:
+			return ErrTrueResult
+		}
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
 // extractMessage extracts common Message result from given data.
 // Should be called after extractOk or b.Raw() to handle possible errors.
 func extractMessage(data []byte) (*Message, error) {
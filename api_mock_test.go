@@ -0,0 +1,137 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRequest is a Bot API request received by the testAPI.
+// The non-string parameters are kept as the raw JSON.
+type testRequest struct {
+	Method string
+	Params map[string]string
+}
+
+// testAPI is a fake Bot API server recording the requests. The reply
+// function returns the response body for the request, the successful
+// true result is returned if it's nil or returns an empty string.
+//
+// The handler runs on the server goroutines, so it never fails
+// the test itself. The malformed requests are reported once the
+// test is finished instead.
+type testAPI struct {
+	t     *testing.T
+	srv   *httptest.Server
+	reply func(method string, params map[string]string) string
+
+	mu       sync.Mutex
+	requests []testRequest
+	errs     []error
+}
+
+func newTestAPI(t *testing.T, reply func(method string, params map[string]string) string) *testAPI {
+	api := &testAPI{t: t, reply: reply}
+	api.srv = httptest.NewServer(http.HandlerFunc(api.serve))
+
+	t.Cleanup(func() {
+		api.srv.Close()
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		for _, err := range api.errs {
+			t.Errorf("test api: %v", err)
+		}
+	})
+
+	return api
+}
+
+// URL returns the URL to be set in the bot Settings.
+func (api *testAPI) URL() string {
+	return api.srv.URL
+}
+
+// Requests returns the requests received so far.
+func (api *testAPI) Requests() []testRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]testRequest(nil), api.requests...)
+}
+
+// Last returns the last received request.
+func (api *testAPI) Last() testRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if len(api.requests) == 0 {
+		return testRequest{}
+	}
+	return api.requests[len(api.requests)-1]
+}
+
+// Reset forgets the received requests.
+func (api *testAPI) Reset() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.requests = nil
+}
+
+func (api *testAPI) serve(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+
+	params, err := decodeTestParams(r)
+	if err != nil {
+		api.mu.Lock()
+		api.errs = append(api.errs, fmt.Errorf("%s: %w", method, err))
+		api.mu.Unlock()
+
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api.mu.Lock()
+	api.requests = append(api.requests, testRequest{Method: method, Params: params})
+	api.mu.Unlock()
+
+	var resp string
+	if api.reply != nil {
+		resp = api.reply(method, params)
+	}
+	if resp == "" {
+		resp = `{"ok":true,"result":true}`
+	}
+	w.Write([]byte(resp))
+}
+
+func decodeTestParams(r *http.Request) (map[string]string, error) {
+	params := make(map[string]string)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		for k, v := range r.MultipartForm.Value {
+			params[k] = v[0]
+		}
+		return params, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	for k, v := range raw {
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			params[k] = s
+		} else {
+			params[k] = string(v)
+		}
+	}
+	return params, nil
+}
@@ -118,3 +118,57 @@ func TestExtractMessage(t *testing.T) {
 	_, err = extractMessage(data)
 	require.NoError(t, err)
 }
+
+func TestExtractResult(t *testing.T) {
+	var link ChatInviteLink
+	data := []byte(`{"ok":true,"result":{"invite_link":"https://t.me/+abc","subscription_price":10}}`)
+	require.NoError(t, extractResult(data, &link))
+	assert.Equal(t, ChatInviteLink{InviteLink: "https://t.me/+abc", SubscriptionPrice: 10}, link)
+
+	data = []byte(`{"ok":true,"result":true}`)
+	assert.Equal(t, ErrTrueResult, extractResult(data, &link))
+
+	var ok bool
+	require.NoError(t, extractResult(data, &ok))
+	assert.True(t, ok)
+
+	require.NoError(t, extractResult(data, nil))
+
+	var n int
+	assert.Error(t, extractResult([]byte(`{"ok":true,"result":"x"}`), &n))
+}
+
+func TestCall(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	var ok bool
+	require.NoError(t, b.Call("setChatTitle", map[string]string{"title": "x"}, &ok))
+	assert.True(t, ok)
+	req := api.Last()
+	assert.Equal(t, "setChatTitle", req.Method)
+	assert.Equal(t, "x", req.Params["title"])
+
+	err = b.CallFiles("setChatPhoto", map[string]string{"chat_id": "1"},
+		map[string]File{"photo": FromReader(strings.NewReader("data"))}, nil)
+	require.NoError(t, err)
+	req = api.Last()
+	assert.Equal(t, "setChatPhoto", req.Method)
+	assert.Equal(t, map[string]string{"chat_id": "1", "photo": "data"}, req.Params)
+
+	sticker := InputSticker{File: File{FileID: "new"}, Emojis: []string{"🙂"}}
+	require.NoError(t, b.ReplaceSticker(&User{ID: 1}, "set", "old", sticker))
+	req = api.Last()
+	assert.Equal(t, "replaceStickerInSet", req.Method)
+	assert.Equal(t, "old", req.Params["old_sticker"])
+	assert.Contains(t, req.Params["sticker"], `"sticker":"new"`)
+
+	_, err = b.CreateSubscriptionLink(&Chat{ID: 1}, &ChatInviteLink{SubscriptionPeriod: 2592000, SubscriptionPrice: 5})
+	assert.Equal(t, ErrTrueResult, err)
+	req = api.Last()
+	assert.Equal(t, "createChatSubscriptionInviteLink", req.Method)
+	assert.Equal(t, "2592000", req.Params["subscription_period"])
+	assert.Equal(t, "5", req.Params["subscription_price"])
+}
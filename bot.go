@@ -29,11 +29,16 @@ func NewBot(pref Settings) (*Bot, error) {
 		pref.URL = DefaultApiURL
 	}
 
+	if pref.InlineResultTTL <= 0 {
+		pref.InlineResultTTL = time.Hour
+	}
+
 	bot := &Bot{
 		Token:   pref.Token,
 		URL:     pref.URL,
 		handler: pref.Handler,
 		client:  client,
+		results: newResultMetas(pref.InlineResultTTL),
 	}
 
 	if pref.Offline {
@@ -56,7 +61,8 @@ type Bot struct {
 	URL     string
 	handler *Handler
 
-	client *http.Client
+	client  *http.Client
+	results *resultMetas
 }
 
 // Settings represents a utility struct for passing certain
@@ -73,6 +79,10 @@ type Settings struct {
 
 	// Offline allows to create a bot without network for testing purposes.
 	Offline bool
+
+	// InlineResultTTL is the time the metadata of the served inline
+	// results is kept for the chosen results. Defaults to an hour.
+	InlineResultTTL time.Duration
 }
 
 var defaultOnError = func(err error, c Context) {
@@ -84,6 +94,10 @@ var defaultOnError = func(err error, c Context) {
 }
 
 func (b *Bot) OnError(err error, c Context) {
+	if b.handler.onError == nil {
+		defaultOnError(err, c)
+		return
+	}
 	b.handler.onError(err, c)
 }
 
@@ -132,6 +146,9 @@ func (b *Bot) Send(to Recipient, what interface{}, opts ...interface{}) (*Messag
 	}
 
 	sendOpts := extractOptions(opts)
+	if err := sendOpts.ReplyMarkup.Validate(); err != nil {
+		return nil, err
+	}
 
 	switch object := what.(type) {
 	case string:
@@ -278,6 +295,9 @@ func (b *Bot) Copy(to Recipient, msg Editable, options ...interface{}) (*Message
 	}
 
 	sendOpts := extractOptions(options)
+	if err := sendOpts.ReplyMarkup.Validate(); err != nil {
+		return nil, err
+	}
 	b.embedSendOptions(params, sendOpts)
 
 	data, err := b.Raw("copyMessage", params)
@@ -359,6 +379,9 @@ func (b *Bot) Edit(msg Editable, what interface{}, opts ...interface{}) (*Messag
 	}
 
 	sendOpts := extractOptions(opts)
+	if err := sendOpts.ReplyMarkup.Validate(); err != nil {
+		return nil, err
+	}
 	b.embedSendOptions(params, sendOpts)
 
 	data, err := b.Raw(method, params)
@@ -390,10 +413,11 @@ func (b *Bot) EditReplyMarkup(msg Editable, markup *ReplyMarkup) (*Message, erro
 		// will delete reply markup
 		markup = &ReplyMarkup{}
 	}
+	if err := markup.Validate(); err != nil {
+		return nil, err
+	}
 
-	processButtons(markup.InlineKeyboard)
-	data, _ := json.Marshal(markup)
-	params["reply_markup"] = string(data)
+	params["reply_markup"] = marshalMarkup(markup)
 
 	data, err := b.Raw("editMessageReplyMarkup", params)
 	if err != nil {
@@ -423,6 +447,9 @@ func (b *Bot) EditCaption(msg Editable, caption string, opts ...interface{}) (*M
 	}
 
 	sendOpts := extractOptions(opts)
+	if err := sendOpts.ReplyMarkup.Validate(); err != nil {
+		return nil, err
+	}
 	b.embedSendOptions(params, sendOpts)
 
 	data, err := b.Raw("editMessageCaption", params)
@@ -487,6 +514,9 @@ func (b *Bot) EditMedia(msg Editable, media Inputtable, opts ...interface{}) (*M
 	params := make(map[string]string)
 
 	sendOpts := extractOptions(opts)
+	if err := sendOpts.ReplyMarkup.Validate(); err != nil {
+		return nil, err
+	}
 	b.embedSendOptions(params, sendOpts)
 
 	im := media.InputMedia()
@@ -661,19 +691,47 @@ func (b *Bot) Respond(c *Callback, resp ...*CallbackResponse) error {
 // be responded to once, subsequent attempts to respond to the same query
 // will result in an error.
 func (b *Bot) Answer(query *Query, resp *QueryResponse) error {
+	if err := resp.Results.Validate(); err != nil {
+		return err
+	}
+
 	resp.QueryID = query.ID
 
 	for _, result := range resp.Results {
 		result.Process(b)
 	}
 
-	_, err := b.Raw("answerInlineQuery", resp)
-	return err
+	if _, err := b.Raw("answerInlineQuery", resp); err != nil {
+		return err
+	}
+
+	for _, result := range resp.Results {
+		if r, ok := result.(metaResult); ok && r.ResultMeta() != nil {
+			b.RememberResult(query.Sender, result.ResultID(), r.ResultMeta())
+		}
+	}
+	return nil
+}
+
+// RememberResult stores the metadata of the inline result served
+// to the user, to be returned by Context.ResultMeta when they choose it.
+// Answer does it for the results with ResultBase.Meta set.
+func (b *Bot) RememberResult(user *User, id string, meta interface{}) {
+	b.results.remember(user, id, meta)
+}
+
+// ResultMeta returns the metadata of the inline result served to the user.
+func (b *Bot) ResultMeta(user *User, id string) (interface{}, bool) {
+	return b.results.lookup(user, id)
 }
 
 // AnswerWebApp sends a response for a query from Web App and returns
 // information about an inline message sent by a Web App on behalf of a user
 func (b *Bot) AnswerWebApp(query *Query, r Result) (*WebAppMessage, error) {
+	if err := (Results{r}).Validate(); err != nil {
+		return nil, err
+	}
+
 	r.Process(b)
 
 	params := map[string]interface{}{
@@ -1098,16 +1156,9 @@ func (b *Bot) botInfo(language, key string) (*BotInfo, error) {
 		"language_code": language,
 	}
 
-	data, err := b.Raw(key, params)
-	if err != nil {
+	var info *BotInfo
+	if err := b.Call(key, params, &info); err != nil {
 		return nil, err
 	}
-
-	var resp struct {
-		Result *BotInfo
-	}
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, wrapError(err)
-	}
-	return resp.Result, nil
+	return info, nil
 }
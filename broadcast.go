@@ -0,0 +1,327 @@
+package telebot
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// RecipientIterator yields the recipients of the broadcast one by one.
+type RecipientIterator interface {
+	// Next returns the next recipient, or nil when there are no more.
+	Next() (Recipient, error)
+}
+
+// Recipients returns the iterator over the list of recipients.
+func Recipients(list ...Recipient) RecipientIterator {
+	return &recipientList{list: list}
+}
+
+type recipientList struct {
+	list []Recipient
+	i    int
+}
+
+func (r *recipientList) Next() (Recipient, error) {
+	if r.i >= len(r.list) {
+		return nil, nil
+	}
+	r.i++
+	return r.list[r.i-1], nil
+}
+
+var (
+	ErrBroadcastPaused  = errors.New("telebot: broadcast is paused")
+	ErrBroadcastRunning = errors.New("telebot: broadcast is already running")
+)
+
+// BroadcastConfig defines the broadcast.
+type BroadcastConfig struct {
+	// What is the message to be sent, anything Send accepts.
+	What interface{}
+
+	// Copy is the message to be copied instead of sending What.
+	Copy Editable
+
+	// Options are the send options of each message.
+	Options []interface{}
+
+	// Rate is the number of messages sent per second. Telegram allows
+	// about 30 messages per second to different chats, so it defaults to 25.
+	Rate int
+
+	// Retries is the number of retries after the FloodError,
+	// waiting for the time Telegram asks to. Defaults to 3.
+	Retries int
+
+	// Offset is the checkpoint to resume the broadcast from,
+	// i.e. the number of recipients already processed.
+	// They are skipped from the iterator.
+	Offset int
+
+	// OnProgress is called after each processed recipient.
+	OnProgress func(BroadcastProgress)
+}
+
+// BroadcastProgress is a live state of the broadcast.
+type BroadcastProgress struct {
+	// Processed is the number of processed recipients, including the
+	// skipped Offset. Save it as a checkpoint to resume the broadcast.
+	Processed int
+
+	Sent   int
+	Failed int
+}
+
+// BroadcastReport describes the failures of the broadcast.
+type BroadcastReport struct {
+	BroadcastProgress
+
+	// Recipients failed with ErrBlockedByUser.
+	Blocked []Recipient
+
+	// Recipients failed with ErrUserIsDeactivated.
+	Deactivated []Recipient
+
+	// Recipients failed with ErrChatNotFound.
+	NotFound []Recipient
+
+	// Errors are the other failures by the recipient IDs.
+	Errors map[string]error
+}
+
+// Broadcaster delivers the message to many recipients, pacing
+// the sends under the rate limit and collecting the report.
+//
+// Example:
+//
+//	br := b.Broadcast(tele.Recipients(users...), tele.BroadcastConfig{
+//		What: "📣 We've got an update!",
+//		OnProgress: func(p tele.BroadcastProgress) {
+//			db.SaveCheckpoint(p.Processed)
+//		},
+//	})
+//
+//	go func() {
+//		if err := br.Run(); err != nil { ... }
+//		report := br.Report()
+//		db.DisableUsers(report.Blocked, report.Deactivated)
+//	}()
+type Broadcaster struct {
+	b   *Bot
+	to  RecipientIterator
+	cfg BroadcastConfig
+
+	mu      sync.Mutex
+	report  BroadcastReport
+	running bool
+	pause   chan struct{}
+	skipped bool
+}
+
+// Broadcast returns the broadcaster of the message to the recipients.
+// Call its Run to start sending.
+func (b *Bot) Broadcast(to RecipientIterator, cfg BroadcastConfig) *Broadcaster {
+	if cfg.Rate <= 0 {
+		cfg.Rate = 25
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = 3
+	}
+
+	return &Broadcaster{
+		b:   b,
+		to:  to,
+		cfg: cfg,
+		report: BroadcastReport{
+			BroadcastProgress: BroadcastProgress{Processed: cfg.Offset},
+			Errors:            make(map[string]error),
+		},
+	}
+}
+
+// Run sends the messages until all the recipients are processed.
+// It returns ErrBroadcastPaused if Pause is called, calling Run
+// again resumes the broadcast from where it stopped.
+func (br *Broadcaster) Run() error {
+	br.mu.Lock()
+	if br.running {
+		br.mu.Unlock()
+		return ErrBroadcastRunning
+	}
+	br.running = true
+	br.pause = make(chan struct{})
+	pause := br.pause
+	br.mu.Unlock()
+
+	defer func() {
+		br.mu.Lock()
+		br.running = false
+		br.mu.Unlock()
+	}()
+
+	if !br.skipped {
+		for i := 0; i < br.cfg.Offset; i++ {
+			if to, err := br.to.Next(); err != nil || to == nil {
+				return err
+			}
+		}
+		br.skipped = true
+	}
+
+	interval := time.Second / time.Duration(br.cfg.Rate)
+	var last time.Time
+
+	for {
+		select {
+		case <-pause:
+			return ErrBroadcastPaused
+		default:
+		}
+
+		to, err := br.to.Next()
+		if err != nil {
+			return err
+		}
+		if to == nil {
+			return nil
+		}
+
+		for retry := 0; ; retry++ {
+			if !sleepUntil(last.Add(interval), pause) {
+				// The recipient wasn't processed, so it's sent on resume.
+				br.to = prepend(to, br.to)
+				return ErrBroadcastPaused
+			}
+			last = time.Now()
+
+			err = br.send(to)
+
+			var flood FloodError
+			if !errors.As(err, &flood) || retry >= br.cfg.Retries {
+				break
+			}
+			last = last.Add(time.Duration(flood.RetryAfter) * time.Second)
+		}
+
+		br.record(to, err)
+	}
+}
+
+func (br *Broadcaster) send(to Recipient) error {
+	var err error
+	if br.cfg.Copy != nil {
+		_, err = br.b.Copy(to, br.cfg.Copy, br.cfg.Options...)
+	} else {
+		_, err = br.b.Send(to, br.cfg.What, br.cfg.Options...)
+	}
+	return err
+}
+
+func (br *Broadcaster) record(to Recipient, err error) {
+	br.mu.Lock()
+
+	r := &br.report
+	r.Processed++
+	switch {
+	case err == nil:
+		r.Sent++
+	case errors.Is(err, ErrBlockedByUser):
+		r.Blocked = append(r.Blocked, to)
+	case errors.Is(err, ErrUserIsDeactivated):
+		r.Deactivated = append(r.Deactivated, to)
+	case errors.Is(err, ErrChatNotFound):
+		r.NotFound = append(r.NotFound, to)
+	default:
+		r.Errors[to.Recipient()] = err
+	}
+	if err != nil {
+		r.Failed++
+	}
+
+	progress := r.BroadcastProgress
+	br.mu.Unlock()
+
+	if br.cfg.OnProgress != nil {
+		br.cfg.OnProgress(progress)
+	}
+}
+
+// Pause stops the running broadcast as soon as the current message is sent.
+func (br *Broadcaster) Pause() {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	if br.running {
+		select {
+		case <-br.pause:
+		default:
+			close(br.pause)
+		}
+	}
+}
+
+// Progress returns the current progress of the broadcast.
+func (br *Broadcaster) Progress() BroadcastProgress {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	return br.report.BroadcastProgress
+}
+
+// Report returns the copy of the current report of the broadcast.
+func (br *Broadcaster) Report() BroadcastReport {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	r := br.report
+	r.Blocked = append([]Recipient(nil), r.Blocked...)
+	r.Deactivated = append([]Recipient(nil), r.Deactivated...)
+	r.NotFound = append([]Recipient(nil), r.NotFound...)
+	r.Errors = make(map[string]error, len(br.report.Errors))
+	for k, v := range br.report.Errors {
+		r.Errors[k] = v
+	}
+	return r
+}
+
+// sleepUntil waits until t, returning false if interrupted.
+func sleepUntil(t time.Time, interrupt <-chan struct{}) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		select {
+		case <-interrupt:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-interrupt:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// prepend returns the iterator yielding the recipient first.
+func prepend(to Recipient, it RecipientIterator) RecipientIterator {
+	return &prepended{to: to, it: it}
+}
+
+type prepended struct {
+	to Recipient
+	it RecipientIterator
+}
+
+func (p *prepended) Next() (Recipient, error) {
+	if p.to != nil {
+		to := p.to
+		p.to = nil
+		return to, nil
+	}
+	return p.it.Next()
+}
@@ -0,0 +1,83 @@
+package telebot
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcast(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		sent    []string
+		flooded bool
+	)
+
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch params["chat_id"] {
+		case "2":
+			return `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`
+		case "3":
+			return `{"ok":false,"error_code":403,"description":"Forbidden: user is deactivated"}`
+		case "4":
+			return `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+		case "5":
+			if !flooded {
+				flooded = true
+				return `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 0","parameters":{"retry_after":0}}`
+			}
+		}
+
+		sent = append(sent, params["chat_id"])
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	recipients := []Recipient{
+		&Chat{ID: 1}, &Chat{ID: 2}, &Chat{ID: 3},
+		&Chat{ID: 4}, &Chat{ID: 5}, &Chat{ID: 6},
+	}
+
+	var br *Broadcaster
+	br = b.Broadcast(Recipients(recipients...), BroadcastConfig{
+		What: "hello",
+		Rate: 1000,
+		OnProgress: func(p BroadcastProgress) {
+			if p.Processed == 2 {
+				br.Pause()
+			}
+		},
+	})
+
+	assert.ErrorIs(t, br.Run(), ErrBroadcastPaused)
+	assert.Equal(t, BroadcastProgress{Processed: 2, Sent: 1, Failed: 1}, br.Progress())
+
+	require.NoError(t, br.Run())
+
+	report := br.Report()
+	assert.Equal(t, BroadcastProgress{Processed: 6, Sent: 3, Failed: 3}, report.BroadcastProgress)
+	assert.Equal(t, []Recipient{&Chat{ID: 2}}, report.Blocked)
+	assert.Equal(t, []Recipient{&Chat{ID: 3}}, report.Deactivated)
+	assert.Equal(t, []Recipient{&Chat{ID: 4}}, report.NotFound)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, []string{"1", "5", "6"}, sent)
+
+	// Resume from the checkpoint.
+	sent = nil
+	br = b.Broadcast(Recipients(recipients...), BroadcastConfig{
+		Copy:   &Message{ID: 1, Chat: &Chat{ID: 10}},
+		Rate:   1000,
+		Offset: 4,
+	})
+
+	require.NoError(t, br.Run())
+	assert.Equal(t, []string{"5", "6"}, sent)
+	assert.Equal(t, 6, br.Progress().Processed)
+}
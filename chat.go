@@ -251,6 +251,14 @@ type ChatInviteLink struct {
 
 	// (Optional) Number of pending join requests created using this link.
 	PendingCount int `json:"pending_join_request_count"`
+
+	// (Optional) The number of seconds the subscription will be active
+	// for before the next payment.
+	SubscriptionPeriod int `json:"subscription_period,omitempty"`
+
+	// (Optional) The amount of Telegram Stars a user must pay initially
+	// and after each subsequent subscription period to be a member of the chat.
+	SubscriptionPrice int `json:"subscription_price,omitempty"`
 }
 
 type Story struct {
@@ -363,6 +371,39 @@ func (b *Bot) EditInviteLink(chat Recipient, link *ChatInviteLink) (*ChatInviteL
 	return &resp.Result, nil
 }
 
+// CreateSubscriptionLink creates a subscription invite link for a channel
+// chat. The link's SubscriptionPeriod must be 30 days (2592000 seconds),
+// SubscriptionPrice is the number of Telegram Stars paid for each period.
+func (b *Bot) CreateSubscriptionLink(chat Recipient, link *ChatInviteLink) (*ChatInviteLink, error) {
+	params := map[string]string{
+		"chat_id":             chat.Recipient(),
+		"name":                link.Name,
+		"subscription_period": strconv.Itoa(link.SubscriptionPeriod),
+		"subscription_price":  strconv.Itoa(link.SubscriptionPrice),
+	}
+
+	var created ChatInviteLink
+	if err := b.Call("createChatSubscriptionInviteLink", params, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// EditSubscriptionLink edits the name of a subscription invite link created by the bot.
+func (b *Bot) EditSubscriptionLink(chat Recipient, link *ChatInviteLink) (*ChatInviteLink, error) {
+	params := map[string]string{
+		"chat_id":     chat.Recipient(),
+		"invite_link": link.InviteLink,
+		"name":        link.Name,
+	}
+
+	var edited ChatInviteLink
+	if err := b.Call("editChatSubscriptionInviteLink", params, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
+
 // RevokeInviteLink revokes an invite link created by the bot.
 func (b *Bot) RevokeInviteLink(chat Recipient, link string) (*ChatInviteLink, error) {
 	params := map[string]string{
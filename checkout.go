@@ -0,0 +1,462 @@
+package telebot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OrderState is a state of the order tracked by the Checkout.
+type OrderState = string
+
+const (
+	OrderShipping  OrderState = "shipping"
+	OrderConfirmed OrderState = "confirmed"
+	OrderRejected  OrderState = "rejected"
+	OrderPaid      OrderState = "paid"
+	OrderRefunded  OrderState = "refunded"
+)
+
+const (
+	// DefaultCheckoutTimeout is the default Checkout.Timeout.
+	// Telegram waits for the answer for 10 seconds.
+	DefaultCheckoutTimeout = 8 * time.Second
+
+	// DefaultOrderTTL is the default Checkout.OrderTTL.
+	DefaultOrderTTL = 24 * time.Hour
+
+	// DefaultCheckoutError is the default Checkout.ErrorMessage.
+	DefaultCheckoutError = "Sorry, this order can't be processed right now."
+)
+
+var ErrCheckoutTimeout = errors.New("telebot: checkout answer timed out")
+
+// CheckoutRejection is an error of ShippingCalculator or OrderValidator
+// with the message shown to the user. Other errors are reported to
+// the error handler, and the user sees Checkout.ErrorMessage instead.
+type CheckoutRejection struct {
+	Message string
+}
+
+func (r *CheckoutRejection) Error() string {
+	return "telebot: checkout rejected: " + r.Message
+}
+
+// RejectOrder returns the CheckoutRejection with the message.
+func RejectOrder(message string) error {
+	return &CheckoutRejection{Message: message}
+}
+
+// ShippingCalculator returns the shipping options for the address of the query.
+type ShippingCalculator func(c Context, q *ShippingQuery) ([]ShippingOption, error)
+
+// OrderValidator checks the order right before the payment,
+// e.g. that the goods are still in stock.
+type OrderValidator func(c Context, q *PreCheckoutQuery) error
+
+// CheckoutOrder is the order of the user paying the invoice.
+type CheckoutOrder struct {
+	User    *User
+	Payload string
+	State   OrderState
+
+	Currency string
+	Total    int
+	OptionID string
+	Info     Order
+
+	// Payment is set once the order is paid.
+	Payment *Payment
+
+	// History lists all the state transitions of the order.
+	History []OrderTransition
+}
+
+// OrderTransition is a change of the order state.
+type OrderTransition struct {
+	From, To OrderState
+	At       time.Time
+
+	// Reason is the rejection reason.
+	Reason string
+}
+
+// PaymentCompleted is the event of the successful payment.
+type PaymentCompleted struct {
+	Payment *Payment
+
+	// Order is the order matched by the sender and the invoice payload.
+	Order CheckoutOrder
+}
+
+// Checkout answers the shipping and pre-checkout queries using
+// the calculators and validators registered for the invoice payload.
+// A payload "pro|123" is matched by "pro|123" or by "pro" key.
+// The zero value is ready to use.
+//
+// Example:
+//
+//	co := tele.NewCheckout()
+//	co.Shipping("pro", calculateShipping)
+//	co.Validate("pro", checkStock)
+//	co.OnPaid = func(c tele.Context, e *tele.PaymentCompleted) error {
+//		return deliver(e.Order)
+//	}
+//	co.Register(handler)
+type Checkout struct {
+	// Timeout is the time given to the calculators and validators.
+	// Defaults to DefaultCheckoutTimeout.
+	Timeout time.Duration
+
+	// ErrorMessage is shown to the user on the internal errors,
+	// timeouts and the payloads with no calculator registered.
+	// Defaults to DefaultCheckoutError.
+	ErrorMessage string
+
+	// OrderTTL is how long the order is kept since its last
+	// transition. Defaults to DefaultOrderTTL. The orders rejected
+	// before reaching any other state aren't kept at all.
+	OrderTTL time.Duration
+
+	// OnTransition is called on each order state change,
+	// e.g. to persist the orders.
+	OnTransition func(CheckoutOrder)
+
+	// OnPaid is called on the successful payment.
+	OnPaid func(Context, *PaymentCompleted) error
+
+	mu         sync.Mutex
+	shipping   map[string]ShippingCalculator
+	validators map[string]OrderValidator
+	orders     map[string]*CheckoutOrder
+	swept      time.Time
+}
+
+// NewCheckout creates the checkout with the default settings.
+func NewCheckout() *Checkout {
+	return &Checkout{
+		Timeout:      DefaultCheckoutTimeout,
+		ErrorMessage: DefaultCheckoutError,
+	}
+}
+
+// Shipping registers the shipping calculator for the invoice payload.
+func (co *Checkout) Shipping(payload string, calc ShippingCalculator) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	if co.shipping == nil {
+		co.shipping = make(map[string]ShippingCalculator)
+	}
+	co.shipping[payload] = calc
+}
+
+// Validate registers the order validator for the invoice payload.
+// The orders with no validator are accepted.
+func (co *Checkout) Validate(payload string, v OrderValidator) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	if co.validators == nil {
+		co.validators = make(map[string]OrderValidator)
+	}
+	co.validators[payload] = v
+}
+
+// Register sets the shipping, pre-checkout, payment and refund
+// handlers of the checkout, replacing the ones set before. The payments
+// and refunds of the orders the checkout doesn't track, e.g. recurring
+// subscription payments, are passed to the replaced handlers instead.
+// Register the handlers after the checkout ones and call HandlePayment
+// and HandleRefund from them to chain it the other way around.
+func (co *Checkout) Register(h *Handler, m ...MiddlewareFunc) {
+	h.Handle(OnShipping, co.handleShipping, m...)
+	h.Handle(OnCheckout, co.handleCheckout, m...)
+
+	co.chain(h, OnPayment, co.HandlePayment, func(c Context) string {
+		return c.Message().Payment.Payload
+	}, m)
+	co.chain(h, OnRefund, co.HandleRefund, func(c Context) string {
+		return c.Message().RefundedPayment.Payload
+	}, m)
+}
+
+// chain sets the checkout handler for the endpoint, passing
+// the updates of the untracked orders to the previous handler.
+func (co *Checkout) chain(h *Handler, end string, hf HandlerFunc, payload func(Context) string, m []MiddlewareFunc) {
+	prev, ok := h.handlers[end]
+	h.Handle(end, hf, m...)
+	if !ok {
+		return
+	}
+
+	handler := h.handlers[end]
+	h.handlers[end] = func(c Context) error {
+		if !co.tracks(c.Sender(), payload(c)) {
+			return prev(c)
+		}
+		return handler(c)
+	}
+}
+
+// tracks reports whether there is the order of the user for the payload.
+func (co *Checkout) tracks(user *User, payload string) bool {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	_, ok := co.orders[orderKey(user, payload)]
+	return ok
+}
+
+// Order returns the order of the user for the invoice payload.
+func (co *Checkout) Order(user *User, payload string) (CheckoutOrder, bool) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	order, ok := co.orders[orderKey(user, payload)]
+	if !ok {
+		return CheckoutOrder{}, false
+	}
+	return order.copy(), true
+}
+
+// Forget removes the order, e.g. once it's delivered.
+func (co *Checkout) Forget(user *User, payload string) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+	delete(co.orders, orderKey(user, payload))
+}
+
+func (co *Checkout) calculator(payload string) ShippingCalculator {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	if calc, ok := co.shipping[payload]; ok {
+		return calc
+	}
+	return co.shipping[payloadPrefix(payload)]
+}
+
+func (co *Checkout) validator(payload string) OrderValidator {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	if v, ok := co.validators[payload]; ok {
+		return v
+	}
+	return co.validators[payloadPrefix(payload)]
+}
+
+func (co *Checkout) handleShipping(c Context) error {
+	q := c.ShippingQuery()
+
+	calc := co.calculator(q.Payload)
+	if calc == nil {
+		co.update(q.Sender, q.Payload, OrderRejected, "no shipping calculator", nil)
+		return c.Ship(co.errorMessage())
+	}
+
+	var opts []ShippingOption
+	err := co.await(func() (err error) {
+		opts, err = calc(c, q)
+		return err
+	})
+	if err == nil && len(opts) == 0 {
+		err = RejectOrder(co.errorMessage())
+	}
+	if err != nil {
+		return co.reject(err, q.Sender, q.Payload, func(msg string) error {
+			return c.Ship(msg)
+		})
+	}
+
+	co.update(q.Sender, q.Payload, OrderShipping, "", func(o *CheckoutOrder) {
+		o.Info.Address = q.Address
+	})
+
+	what := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		what[i] = opt
+	}
+	return c.Ship(what...)
+}
+
+func (co *Checkout) handleCheckout(c Context) error {
+	q := c.PreCheckoutQuery()
+
+	if validate := co.validator(q.Payload); validate != nil {
+		err := co.await(func() error {
+			return validate(c, q)
+		})
+		if err != nil {
+			return co.reject(err, q.Sender, q.Payload, func(msg string) error {
+				return c.Accept(msg)
+			})
+		}
+	}
+
+	co.update(q.Sender, q.Payload, OrderConfirmed, "", func(o *CheckoutOrder) {
+		o.Currency = q.Currency
+		o.Total = q.Total
+		o.OptionID = q.OptionID
+		o.Info = q.Order
+	})
+	return c.Accept()
+}
+
+// HandlePayment marks the order of the successful payment
+// as paid and calls OnPaid.
+func (co *Checkout) HandlePayment(c Context) error {
+	p := c.Message().Payment
+
+	order := co.update(c.Sender(), p.Payload, OrderPaid, "", func(o *CheckoutOrder) {
+		o.Currency = p.Currency
+		o.Total = p.Total
+		o.OptionID = p.OptionID
+		o.Info = p.Order
+		o.Payment = p
+	})
+
+	if co.OnPaid == nil {
+		return nil
+	}
+	return co.OnPaid(c, &PaymentCompleted{Payment: p, Order: order})
+}
+
+// HandleRefund marks the order of the refunded payment as refunded.
+func (co *Checkout) HandleRefund(c Context) error {
+	co.update(c.Sender(), c.Message().RefundedPayment.Payload, OrderRefunded, "", nil)
+	return nil
+}
+
+// await runs fn, giving up after the timeout.
+func (co *Checkout) await(fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	timeout := co.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCheckoutTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return ErrCheckoutTimeout
+	}
+}
+
+// reject answers the query with the rejection message. The internal
+// errors are returned to be reported to the error handler.
+func (co *Checkout) reject(err error, user *User, payload string, answer func(string) error) error {
+	message, reason := co.errorMessage(), err.Error()
+
+	var rejection *CheckoutRejection
+	if errors.As(err, &rejection) {
+		message, reason, err = rejection.Message, rejection.Message, nil
+	}
+
+	co.update(user, payload, OrderRejected, reason, nil)
+	if aerr := answer(message); aerr != nil {
+		return aerr
+	}
+	return err
+}
+
+func (co *Checkout) errorMessage() string {
+	if co.ErrorMessage == "" {
+		return DefaultCheckoutError
+	}
+	return co.ErrorMessage
+}
+
+// update moves the order to the state, creating it if needed,
+// and returns its copy.
+func (co *Checkout) update(user *User, payload string, state OrderState, reason string, fn func(*CheckoutOrder)) CheckoutOrder {
+	co.mu.Lock()
+
+	now := time.Now()
+	co.sweep(now)
+
+	key := orderKey(user, payload)
+	order, ok := co.orders[key]
+	if !ok {
+		order = &CheckoutOrder{User: user, Payload: payload}
+	}
+
+	order.History = append(order.History, OrderTransition{
+		From:   order.State,
+		To:     state,
+		At:     now,
+		Reason: reason,
+	})
+	order.State = state
+	if fn != nil {
+		fn(order)
+	}
+
+	// The order rejected right away is reported, but not kept,
+	// so the unknown payloads don't pile up.
+	if !ok && state != OrderRejected {
+		if co.orders == nil {
+			co.orders = make(map[string]*CheckoutOrder)
+		}
+		co.orders[key] = order
+	}
+
+	cp := order.copy()
+	co.mu.Unlock()
+
+	if co.OnTransition != nil {
+		co.OnTransition(cp)
+	}
+	return cp
+}
+
+// sweep removes the expired orders, at most once per the order TTL.
+func (co *Checkout) sweep(now time.Time) {
+	ttl := co.OrderTTL
+	if ttl <= 0 {
+		ttl = DefaultOrderTTL
+	}
+	if now.Sub(co.swept) < ttl {
+		return
+	}
+
+	for key, order := range co.orders {
+		if now.Sub(order.History[len(order.History)-1].At) >= ttl {
+			delete(co.orders, key)
+		}
+	}
+	co.swept = now
+}
+
+func (o *CheckoutOrder) copy() CheckoutOrder {
+	cp := *o
+	cp.History = append([]OrderTransition(nil), o.History...)
+	return cp
+}
+
+func orderKey(user *User, payload string) string {
+	var id int64
+	if user != nil {
+		id = user.ID
+	}
+	return strconv.FormatInt(id, 10) + "|" + payload
+}
+
+// payloadPrefix returns the part of the payload before the "|" separator.
+func payloadPrefix(payload string) string {
+	if i := strings.IndexByte(payload, '|'); i >= 0 {
+		return payload[:i]
+	}
+	return payload
+}
@@ -0,0 +1,232 @@
+package telebot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckout(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	var errs []error
+	h := NewHandler(HandlerSettings{
+		Synchronous: true,
+		OnError:     func(err error, c Context) { errs = append(errs, err) },
+	})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	var (
+		inStock     = false
+		transitions []OrderState
+		completed   *PaymentCompleted
+	)
+
+	// The slow validator is blocked until the test is over.
+	release := make(chan struct{})
+	defer close(release)
+
+	co := NewCheckout()
+	co.Timeout = 20 * time.Millisecond
+	co.Shipping("pro", func(c Context, q *ShippingQuery) ([]ShippingOption, error) {
+		return []ShippingOption{{ID: "post", Title: "Post", Prices: []Price{{Label: "Post", Amount: 100}}}}, nil
+	})
+	co.Validate("pro", func(c Context, q *PreCheckoutQuery) error {
+		if q.Total == 0 {
+			<-release
+			return nil
+		}
+		if !inStock {
+			return RejectOrder("Out of stock")
+		}
+		return nil
+	})
+	co.OnTransition = func(o CheckoutOrder) {
+		transitions = append(transitions, o.State)
+	}
+	co.OnPaid = func(c Context, e *PaymentCompleted) error {
+		completed = e
+		return nil
+	}
+	co.Register(h)
+
+	user := &User{ID: 1}
+
+	b.ProcessUpdate(Update{ShippingQuery: &ShippingQuery{ID: "1", Sender: user, Payload: "pro|1"}})
+	assert.Equal(t, "true", api.Last().Params["ok"])
+	assert.JSONEq(t, `[{"id":"post","title":"Post","prices":[{"label":"Post","amount":100}]}]`, api.Last().Params["shipping_options"])
+
+	b.ProcessUpdate(Update{ShippingQuery: &ShippingQuery{ID: "2", Sender: user, Payload: "basic"}})
+	assert.Equal(t, "false", api.Last().Params["ok"])
+	assert.Equal(t, co.ErrorMessage, api.Last().Params["error_message"])
+	_, ok := co.Order(user, "basic")
+	assert.False(t, ok)
+
+	checkout := &PreCheckoutQuery{ID: "3", Sender: user, Payload: "pro|1", Currency: "USD", Total: 1100}
+	b.ProcessUpdate(Update{PreCheckoutQuery: checkout})
+	assert.Equal(t, "Out of stock", api.Last().Params["error_message"])
+	assert.Empty(t, errs)
+
+	b.ProcessUpdate(Update{PreCheckoutQuery: &PreCheckoutQuery{ID: "4", Sender: user, Payload: "pro|1"}})
+	assert.Equal(t, co.ErrorMessage, api.Last().Params["error_message"])
+	require.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], ErrCheckoutTimeout))
+
+	inStock = true
+	b.ProcessUpdate(Update{PreCheckoutQuery: checkout})
+	assert.Equal(t, map[string]string{"pre_checkout_query_id": "3", "ok": "true"}, api.Last().Params)
+
+	order, ok := co.Order(user, "pro|1")
+	require.True(t, ok)
+	assert.Equal(t, OrderConfirmed, order.State)
+	assert.Equal(t, 1100, order.Total)
+
+	payment := &Payment{Currency: "USD", Total: 1100, Payload: "pro|1", OptionID: "post"}
+	b.ProcessUpdate(Update{Message: &Message{Sender: user, Chat: &Chat{ID: 1}, Payment: payment}})
+	require.NotNil(t, completed)
+	assert.Equal(t, payment, completed.Payment)
+	assert.Equal(t, OrderPaid, completed.Order.State)
+	assert.Equal(t, "post", completed.Order.OptionID)
+
+	b.ProcessUpdate(Update{Message: &Message{
+		Sender:          user,
+		Chat:            &Chat{ID: 1},
+		RefundedPayment: &RefundedPayment{Payload: "pro|1"},
+	}})
+
+	assert.Equal(t, []OrderState{
+		OrderShipping, OrderRejected, OrderRejected, OrderRejected,
+		OrderConfirmed, OrderPaid, OrderRefunded,
+	}, transitions)
+
+	order, _ = co.Order(user, "pro|1")
+	assert.Equal(t, OrderRefunded, order.State)
+	assert.Len(t, order.History, 6)
+	assert.Equal(t, "Out of stock", order.History[1].Reason)
+
+	co.Forget(user, "pro|1")
+	_, ok = co.Order(user, "pro|1")
+	assert.False(t, ok)
+}
+
+func TestCheckoutZero(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	h := NewHandler(HandlerSettings{Synchronous: true})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	co := &Checkout{}
+	co.Register(h)
+
+	user := &User{ID: 1}
+
+	b.ProcessUpdate(Update{ShippingQuery: &ShippingQuery{ID: "1", Sender: user, Payload: "pro"}})
+	assert.Equal(t, "false", api.Last().Params["ok"])
+	assert.Equal(t, DefaultCheckoutError, api.Last().Params["error_message"])
+
+	co.Validate("pro", func(c Context, q *PreCheckoutQuery) error { return nil })
+	b.ProcessUpdate(Update{PreCheckoutQuery: &PreCheckoutQuery{ID: "2", Sender: user, Payload: "pro"}})
+	assert.Equal(t, "true", api.Last().Params["ok"])
+
+	order, ok := co.Order(user, "pro")
+	require.True(t, ok)
+	assert.Equal(t, OrderConfirmed, order.State)
+}
+
+func TestCheckoutOrderTTL(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	h := NewHandler(HandlerSettings{Synchronous: true})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	co := NewCheckout()
+	co.OrderTTL = time.Hour
+	co.Register(h)
+
+	first, second := &User{ID: 1}, &User{ID: 2}
+
+	b.ProcessUpdate(Update{PreCheckoutQuery: &PreCheckoutQuery{ID: "1", Sender: first, Payload: "pro"}})
+	_, ok := co.Order(first, "pro")
+	require.True(t, ok)
+
+	// Make the order look an hour old.
+	co.mu.Lock()
+	for _, order := range co.orders {
+		for i := range order.History {
+			order.History[i].At = order.History[i].At.Add(-time.Hour)
+		}
+	}
+	co.swept = time.Time{}
+	co.mu.Unlock()
+
+	b.ProcessUpdate(Update{PreCheckoutQuery: &PreCheckoutQuery{ID: "2", Sender: second, Payload: "pro"}})
+	_, ok = co.Order(first, "pro")
+	assert.False(t, ok)
+	_, ok = co.Order(second, "pro")
+	assert.True(t, ok)
+}
+
+func TestCheckoutRegisterChain(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	h := NewHandler(HandlerSettings{Synchronous: true})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	var payments, refunds []string
+	h.Handle(OnPayment, func(c Context) error {
+		payments = append(payments, c.Message().Payment.Payload)
+		return nil
+	})
+	h.Handle(OnRefund, func(c Context) error {
+		refunds = append(refunds, c.Message().RefundedPayment.Payload)
+		return nil
+	})
+
+	var paid []string
+	co := NewCheckout()
+	co.OnPaid = func(c Context, e *PaymentCompleted) error {
+		paid = append(paid, e.Payment.Payload)
+		return nil
+	}
+	co.Register(h)
+
+	user := &User{ID: 1}
+	b.ProcessUpdate(Update{PreCheckoutQuery: &PreCheckoutQuery{ID: "1", Sender: user, Payload: "pro"}})
+
+	pay := func(payload string) {
+		b.ProcessUpdate(Update{Message: &Message{
+			Sender:  user,
+			Chat:    &Chat{ID: 1},
+			Payment: &Payment{Payload: payload},
+		}})
+	}
+	refund := func(payload string) {
+		b.ProcessUpdate(Update{Message: &Message{
+			Sender:          user,
+			Chat:            &Chat{ID: 1},
+			RefundedPayment: &RefundedPayment{Payload: payload},
+		}})
+	}
+
+	pay("pro")
+	pay("subscription")
+	refund("pro")
+	refund("subscription")
+
+	assert.Equal(t, []string{"pro"}, paid)
+	assert.Equal(t, []string{"subscription"}, payments)
+	assert.Equal(t, []string{"subscription"}, refunds)
+
+	order, ok := co.Order(user, "pro")
+	require.True(t, ok)
+	assert.Equal(t, OrderRefunded, order.State)
+	_, ok = co.Order(user, "subscription")
+	assert.False(t, ok)
+}
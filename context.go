@@ -37,6 +37,10 @@ type Context interface {
 	// InlineResult returns stored inline result if such presented.
 	InlineResult() *InlineResult
 
+	// ResultMeta returns the metadata remembered for the chosen
+	// inline result when it was served, if such presented.
+	ResultMeta() interface{}
+
 	// ShippingQuery returns stored shipping query if such presented.
 	ShippingQuery() *ShippingQuery
 
@@ -61,6 +65,17 @@ type Context interface {
 	// Topic returns the topic changes.
 	Topic() *Topic
 
+	// Endpoint returns the endpoint the current handler was registered for,
+	// e.g. "/start", OnText, or a callback unique prefixed with \f.
+	Endpoint() string
+
+	// Album returns the messages of a media group, sorted by their IDs.
+	// It's only presented in the context of the OnAlbum handler.
+	Album() []Message
+
+	// Contact returns the contact shared in the message, if any.
+	Contact() *Contact
+
 	// Sender returns the current recipient, depending on the context type.
 	// Returns nil if user is not presented.
 	Sender() *User
@@ -121,6 +136,14 @@ type Context interface {
 	// See EditCaption from bot.go.
 	EditCaption(caption string, opts ...interface{}) error
 
+	// EditReplyMarkup edits the reply markup of the current message.
+	// See EditReplyMarkup from bot.go.
+	EditReplyMarkup(markup *ReplyMarkup) error
+
+	// EditMedia edits the media of the current message.
+	// See EditMedia from bot.go.
+	EditMedia(media Inputtable, opts ...interface{}) error
+
 	// EditOrSend edits the current message if the update is callback,
 	// otherwise the content is sent to the chat as a separate message.
 	EditOrSend(what interface{}, opts ...interface{}) error
@@ -174,10 +197,12 @@ type Context interface {
 // nativeContext is a native implementation of the Context interface.
 // "context" is taken by context package, maybe there is a better name.
 type nativeContext struct {
-	b     *Bot
-	u     Update
-	lock  sync.RWMutex
-	store map[string]interface{}
+	b        *Bot
+	u        Update
+	endpoint string
+	album    []Message
+	lock     sync.RWMutex
+	store    map[string]interface{}
 }
 
 func (c *nativeContext) Bot() *Bot {
@@ -228,6 +253,14 @@ func (c *nativeContext) InlineResult() *InlineResult {
 	return c.u.InlineResult
 }
 
+func (c *nativeContext) ResultMeta() interface{} {
+	if c.u.InlineResult == nil {
+		return nil
+	}
+	meta, _ := c.b.ResultMeta(c.u.InlineResult.Sender, c.u.InlineResult.ResultID)
+	return meta
+}
+
 func (c *nativeContext) ShippingQuery() *ShippingQuery {
 	return c.u.ShippingQuery
 }
@@ -279,6 +312,21 @@ func (c *nativeContext) Topic() *Topic {
 	return nil
 }
 
+func (c *nativeContext) Endpoint() string {
+	return c.endpoint
+}
+
+func (c *nativeContext) Album() []Message {
+	return c.album
+}
+
+func (c *nativeContext) Contact() *Contact {
+	if m := c.Message(); m != nil {
+		return m.Contact
+	}
+	return nil
+}
+
 func (c *nativeContext) Sender() *User {
 	switch {
 	case c.u.Callback != nil:
@@ -420,28 +468,63 @@ func (c *nativeContext) ForwardTo(to Recipient, opts ...interface{}) error {
 	return err
 }
 
-func (c *nativeContext) Edit(what interface{}, opts ...interface{}) error {
+// editable returns the message of the chosen inline result or the callback.
+func (c *nativeContext) editable() (Editable, error) {
 	if c.u.InlineResult != nil {
-		_, err := c.b.Edit(c.u.InlineResult, what, opts...)
-		return err
+		if c.u.InlineResult.MessageID == "" {
+			return nil, ErrNoInlineMessage
+		}
+		return c.u.InlineResult, nil
 	}
 	if c.u.Callback != nil {
-		_, err := c.b.Edit(c.u.Callback, what, opts...)
+		return c.u.Callback, nil
+	}
+	return nil, ErrBadContext
+}
+
+// editedInline ignores ErrTrueResult, which is returned
+// on success when editing the inline messages.
+func editedInline(err error) error {
+	if err == ErrTrueResult {
+		return nil
+	}
+	return err
+}
+
+func (c *nativeContext) Edit(what interface{}, opts ...interface{}) error {
+	msg, err := c.editable()
+	if err != nil {
 		return err
 	}
-	return ErrBadContext
+	_, err = c.b.Edit(msg, what, opts...)
+	return editedInline(err)
 }
 
 func (c *nativeContext) EditCaption(caption string, opts ...interface{}) error {
-	if c.u.InlineResult != nil {
-		_, err := c.b.EditCaption(c.u.InlineResult, caption, opts...)
+	msg, err := c.editable()
+	if err != nil {
 		return err
 	}
-	if c.u.Callback != nil {
-		_, err := c.b.EditCaption(c.u.Callback, caption, opts...)
+	_, err = c.b.EditCaption(msg, caption, opts...)
+	return editedInline(err)
+}
+
+func (c *nativeContext) EditReplyMarkup(markup *ReplyMarkup) error {
+	msg, err := c.editable()
+	if err != nil {
+		return err
+	}
+	_, err = c.b.EditReplyMarkup(msg, markup)
+	return editedInline(err)
+}
+
+func (c *nativeContext) EditMedia(media Inputtable, opts ...interface{}) error {
+	msg, err := c.editable()
+	if err != nil {
 		return err
 	}
-	return ErrBadContext
+	_, err = c.b.EditMedia(msg, media, opts...)
+	return editedInline(err)
 }
 
 func (c *nativeContext) EditOrSend(what interface{}, opts ...interface{}) error {
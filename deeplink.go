@@ -0,0 +1,213 @@
+package telebot
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// MaxStartPayload is the maximum length of a deep-linking parameter
+// allowed by Telegram.
+const MaxStartPayload = 64
+
+var (
+	ErrBadStartPayload     = errors.New("telebot: start payload is malformed")
+	ErrTooLongStartPayload = errors.New("telebot: start payload exceeds 64 characters")
+)
+
+var startNameRx = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// EncodePayload marshals v to JSON and encodes it with unpadded base64url,
+// so the result only consists of the characters allowed in deep links.
+func EncodePayload(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", wrapError(err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	if len(payload) > MaxStartPayload {
+		return "", ErrTooLongStartPayload
+	}
+	return payload, nil
+}
+
+// DecodePayload decodes the payload built by EncodePayload into v.
+func DecodePayload(payload string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return ErrBadStartPayload
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// StartPayload builds a start or startgroup parameter in the
+// "<name>-<data>" form, where data is an encoded v. The name is used
+// to route the /start command to the handler registered with the
+// StartEndpoint function. Pass nil v to get the name-only payload.
+//
+// The name may only contain A-Z, a-z, 0-9 and _ characters.
+func StartPayload(name string, v interface{}) (string, error) {
+	if !startNameRx.MatchString(name) {
+		return "", ErrBadStartPayload
+	}
+	if v == nil {
+		return name, nil
+	}
+
+	data, err := EncodePayload(v)
+	if err != nil {
+		return "", err
+	}
+
+	payload := name + "-" + data
+	if len(payload) > MaxStartPayload {
+		return "", ErrTooLongStartPayload
+	}
+	return payload, nil
+}
+
+// ParseStartPayload splits the payload built by StartPayload
+// into its name and encoded data.
+func ParseStartPayload(payload string) (name, data string) {
+	if i := strings.IndexByte(payload, '-'); i >= 0 {
+		return payload[:i], payload[i+1:]
+	}
+	return payload, ""
+}
+
+// DecodeStartPayload decodes the data part of the payload
+// built by StartPayload into v.
+//
+// Example:
+//
+//	b.Handle(tele.StartEndpoint("ref"), func(c tele.Context) error {
+//		var ref Referral
+//		if err := tele.DecodeStartPayload(c.Data(), &ref); err != nil {
+//			return err
+//		}
+//		...
+//	})
+func DecodeStartPayload(payload string, v interface{}) error {
+	_, data := ParseStartPayload(payload)
+	if data == "" {
+		return ErrBadStartPayload
+	}
+	return DecodePayload(data, v)
+}
+
+// StartEndpoint returns an endpoint for the /start command with the
+// payload of the given name. It takes precedence over the /start handler.
+func StartEndpoint(name string) string {
+	return "\astart:" + name
+}
+
+func (b *Bot) handleStartPayload(payload string, c Context) bool {
+	name, _ := ParseStartPayload(payload)
+	return b.handle(StartEndpoint(name), c)
+}
+
+// AttachTarget is a type of chat that can be chosen
+// when opening the bot's attachment menu via link.
+type AttachTarget = string
+
+const (
+	AttachUsers    AttachTarget = "users"
+	AttachBots     AttachTarget = "bots"
+	AttachGroups   AttachTarget = "groups"
+	AttachChannels AttachTarget = "channels"
+)
+
+// StartLink returns a deep link that opens a private chat with the bot
+// and sends the /start command with the given payload.
+func (b *Bot) StartLink(payload string) string {
+	if payload == "" {
+		return b.deepLink("")
+	}
+	return b.deepLink("", "start="+payload)
+}
+
+// StartGroupLink returns a deep link that prompts the user to add
+// the bot to a group, passing the payload to the /start command.
+// Optional rights are requested for the bot as an administrator.
+func (b *Bot) StartGroupLink(payload string, rights ...Rights) string {
+	params := []string{"startgroup"}
+	if payload != "" {
+		params[0] += "=" + payload
+	}
+	if len(rights) > 0 {
+		if admin := adminLinkRights(rights[0]); admin != "" {
+			params = append(params, "admin="+admin)
+		}
+	}
+	return b.deepLink("", params...)
+}
+
+// StartAppLink returns a direct link to the bot's Mini App with
+// the given short name, passing the payload as a start_param.
+// If the app is empty, the link opens the bot's main Mini App.
+func (b *Bot) StartAppLink(app, payload string) string {
+	if payload == "" {
+		return b.deepLink(app, "startapp")
+	}
+	return b.deepLink(app, "startapp="+payload)
+}
+
+// AttachMenuLink returns a link that opens the bot's attachment menu,
+// passing the payload as a start_param. If the targets are specified,
+// the user is first asked to choose a chat of one of these types.
+func (b *Bot) AttachMenuLink(payload string, targets ...AttachTarget) string {
+	params := []string{"startattach"}
+	if payload != "" {
+		params[0] += "=" + payload
+	}
+	if len(targets) > 0 {
+		params = append(params, "choose="+strings.Join(targets, "+"))
+	}
+	return b.deepLink("", params...)
+}
+
+// deepLink builds a t.me link of the bot. Params are expected to be
+// URL-safe already, since the '+' separators must be kept as is.
+func (b *Bot) deepLink(path string, params ...string) string {
+	link := "https://t.me/" + b.Me.Username
+	if path != "" {
+		link += "/" + path
+	}
+	if len(params) > 0 {
+		link += "?" + strings.Join(params, "&")
+	}
+	return link
+}
+
+func adminLinkRights(r Rights) string {
+	var rights []string
+	add := func(ok bool, name string) {
+		if ok {
+			rights = append(rights, name)
+		}
+	}
+
+	add(r.CanChangeInfo, "change_info")
+	add(r.CanPostMessages, "post_messages")
+	add(r.CanEditMessages, "edit_messages")
+	add(r.CanDeleteMessages, "delete_messages")
+	add(r.CanRestrictMembers, "restrict_members")
+	add(r.CanInviteUsers, "invite_users")
+	add(r.CanPinMessages, "pin_messages")
+	add(r.CanManageTopics, "manage_topics")
+	add(r.CanPromoteMembers, "promote_members")
+	add(r.CanManageVideoChats, "manage_video_chats")
+	add(r.Anonymous, "anonymous")
+	add(r.CanManageChat, "manage_chat")
+	add(r.CanPostStories, "post_stories")
+	add(r.CanEditStories, "edit_stories")
+	add(r.CanDeleteStories, "delete_stories")
+
+	return strings.Join(rights, "+")
+}
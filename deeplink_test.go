@@ -0,0 +1,85 @@
+package telebot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartPayload(t *testing.T) {
+	type ref struct {
+		UserID int64  `json:"u"`
+		Source string `json:"s"`
+	}
+
+	payload, err := StartPayload("ref", ref{UserID: 42, Source: "ads"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(payload, "ref-"))
+	assert.LessOrEqual(t, len(payload), MaxStartPayload)
+
+	name, _ := ParseStartPayload(payload)
+	assert.Equal(t, "ref", name)
+
+	var got ref
+	require.NoError(t, DecodeStartPayload(payload, &got))
+	assert.Equal(t, ref{UserID: 42, Source: "ads"}, got)
+
+	payload, err = StartPayload("promo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "promo", payload)
+	assert.Equal(t, ErrBadStartPayload, DecodeStartPayload(payload, &got))
+
+	_, err = StartPayload("bad-name", nil)
+	assert.Equal(t, ErrBadStartPayload, err)
+
+	_, err = StartPayload("ref", strings.Repeat("x", MaxStartPayload))
+	assert.Equal(t, ErrTooLongStartPayload, err)
+}
+
+func TestBotDeepLinks(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+	b.Me.Username = "telebot"
+
+	assert.Equal(t, "https://t.me/telebot", b.StartLink(""))
+	assert.Equal(t, "https://t.me/telebot?start=ref-abc", b.StartLink("ref-abc"))
+	assert.Equal(t, "https://t.me/telebot?startgroup=abc", b.StartGroupLink("abc"))
+	assert.Equal(t,
+		"https://t.me/telebot?startgroup=abc&admin=delete_messages+restrict_members",
+		b.StartGroupLink("abc", Rights{CanDeleteMessages: true, CanRestrictMembers: true}),
+	)
+	assert.Equal(t, "https://t.me/telebot?startapp", b.StartAppLink("", ""))
+	assert.Equal(t, "https://t.me/telebot/shop?startapp=abc", b.StartAppLink("shop", "abc"))
+	assert.Equal(t,
+		"https://t.me/telebot?startattach=abc&choose=users+groups",
+		b.AttachMenuLink("abc", AttachUsers, AttachGroups),
+	)
+}
+
+func TestBotStartPayloadRouting(t *testing.T) {
+	b, err := NewBot(Settings{
+		Handler: NewHandler(HandlerSettings{Synchronous: true}),
+		Offline: true,
+	})
+	require.NoError(t, err)
+
+	var route string
+	b.handler.Handle("/start", func(c Context) error {
+		route = "start"
+		return nil
+	})
+	b.handler.Handle(StartEndpoint("ref"), func(c Context) error {
+		route = "ref"
+		assert.Equal(t, StartEndpoint("ref"), c.Endpoint())
+		assert.Equal(t, "ref-eyJ1Ijo0Mn0", c.Data())
+		return nil
+	})
+
+	b.ProcessUpdate(Update{Message: &Message{Text: "/start ref-eyJ1Ijo0Mn0"}})
+	assert.Equal(t, "ref", route)
+
+	b.ProcessUpdate(Update{Message: &Message{Text: "/start other"}})
+	assert.Equal(t, "start", route)
+}
@@ -0,0 +1,213 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fragment is a piece of the formatted text: either a plain string,
+// or a styled group of nested fragments. Use Bold, Italic, TextLink
+// and other functions to build them.
+type Fragment struct {
+	entity   *MessageEntity
+	children []interface{}
+}
+
+// Bold returns a bold fragment. Parts are either strings or fragments.
+func Bold(parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntityBold}, parts)
+}
+
+// Italic returns an italic fragment.
+func Italic(parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntityItalic}, parts)
+}
+
+// Underline returns an underlined fragment.
+func Underline(parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntityUnderline}, parts)
+}
+
+// Strikethrough returns a strikethrough fragment.
+func Strikethrough(parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntityStrikethrough}, parts)
+}
+
+// Spoiler returns a fragment hidden under the spoiler.
+func Spoiler(parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntitySpoiler}, parts)
+}
+
+// Blockquote returns a quoted fragment.
+func Blockquote(parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntityBlockquote}, parts)
+}
+
+// Code returns an inline monowidth fragment.
+// Code can't contain other styles.
+func Code(text string) Fragment {
+	return styled(MessageEntity{Type: EntityCode}, []interface{}{text})
+}
+
+// Pre returns a pre-formatted code block with the optional
+// programming language. Pre can't contain other styles.
+func Pre(code, language string) Fragment {
+	return styled(MessageEntity{Type: EntityCodeBlock, Language: language}, []interface{}{code})
+}
+
+// TextLink returns a fragment that opens the URL on tap.
+func TextLink(url string, parts ...interface{}) Fragment {
+	return styled(MessageEntity{Type: EntityTextLink, URL: url}, parts)
+}
+
+// Mention returns a fragment mentioning the user, which also works
+// for users without usernames. Mentions the user's first name
+// if no parts are passed.
+func Mention(user *User, parts ...interface{}) Fragment {
+	if len(parts) == 0 {
+		parts = []interface{}{user.FirstName}
+	}
+	return styled(MessageEntity{Type: EntityTMention, User: user}, parts)
+}
+
+// CustomEmoji returns a custom emoji fragment. The emoji is a regular
+// emoji shown in place of the custom one where it's not supported.
+func CustomEmoji(id, emoji string) Fragment {
+	return styled(MessageEntity{Type: EntityCustomEmoji, CustomEmoji: id}, []interface{}{emoji})
+}
+
+func styled(e MessageEntity, parts []interface{}) Fragment {
+	return Fragment{entity: &e, children: parts}
+}
+
+// TextBuilder builds the text along with its entities, calculating their
+// offsets and lengths in UTF-16 code units. The zero value is ready to use.
+//
+// Example:
+//
+//	var tb tele.TextBuilder
+//	tb.Add("Hello, ", tele.Bold("dear ", tele.Italic("friend")), " 👋")
+//	c.Send(tb.String(), tb.Entities())
+type TextBuilder struct {
+	sb       strings.Builder
+	length   int
+	entities Entities
+}
+
+// Add appends the parts to the text. Each part is either a string,
+// a Fragment, or a value formatted with fmt.Sprint.
+func (tb *TextBuilder) Add(parts ...interface{}) *TextBuilder {
+	for _, part := range parts {
+		switch p := part.(type) {
+		case string:
+			tb.write(p)
+		case Fragment:
+			tb.addFragment(p)
+		case *Fragment:
+			tb.addFragment(*p)
+		default:
+			tb.write(fmt.Sprint(p))
+		}
+	}
+	return tb
+}
+
+func (tb *TextBuilder) addFragment(f Fragment) {
+	if f.entity == nil {
+		tb.Add(f.children...)
+		return
+	}
+
+	// Parent entity goes first, so the entities stay
+	// sorted by their offsets, as Telegram expects them.
+	i := len(tb.entities)
+	e := *f.entity
+	e.Offset = tb.length
+	tb.entities = append(tb.entities, e)
+
+	tb.Add(f.children...)
+
+	if length := tb.length - e.Offset; length > 0 {
+		tb.entities[i].Length = length
+	} else {
+		tb.entities = append(tb.entities[:i], tb.entities[i+1:]...)
+	}
+}
+
+func (tb *TextBuilder) write(s string) {
+	tb.sb.WriteString(s)
+	tb.length += utf16Len(s)
+}
+
+// String returns the built text.
+func (tb *TextBuilder) String() string {
+	return tb.sb.String()
+}
+
+// Entities returns the entities of the built text.
+func (tb *TextBuilder) Entities() Entities {
+	return tb.entities
+}
+
+// Len returns the length of the built text in UTF-16 code units,
+// the way Telegram counts it.
+func (tb *TextBuilder) Len() int {
+	return tb.length
+}
+
+// Formatted is a text with its entities. It's a Sendable,
+// which sends the text message with the entities set.
+type Formatted struct {
+	Text     string
+	Entities Entities
+}
+
+// Format builds the Formatted text from the parts. See TextBuilder.Add.
+//
+// Example:
+//
+//	c.Send(tele.Format("Order ", tele.Code("#42"), " is ", tele.Bold("ready")))
+func Format(parts ...interface{}) *Formatted {
+	var tb TextBuilder
+	tb.Add(parts...)
+	return &Formatted{Text: tb.String(), Entities: tb.Entities()}
+}
+
+// Send delivers the formatted text through bot b to recipient.
+// The entities override both parse mode and entities of the options.
+func (f *Formatted) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
+	params := map[string]string{
+		"chat_id": to.Recipient(),
+		"text":    f.Text,
+	}
+	b.embedSendOptions(params, opt)
+
+	delete(params, "parse_mode")
+	delete(params, "entities")
+	if len(f.Entities) > 0 {
+		data, _ := json.Marshal(f.Entities)
+		params["entities"] = string(data)
+	}
+
+	data, err := b.Raw("sendMessage", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractMessage(data)
+}
+
+// utf16Len returns the length of the string in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 && r <= utf8.MaxRune {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
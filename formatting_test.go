@@ -0,0 +1,43 @@
+package telebot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextBuilder(t *testing.T) {
+	user := &User{ID: 1, FirstName: "Jon"}
+
+	f := Format(
+		"👋 Hi, ", Mention(user), "! ",
+		Bold("bold ", Italic("nested 🎉"), " tail"),
+		" ", Code("x := 1"),
+		" ", Pre("fmt.Println()", "go"),
+		" ", TextLink("https://go.dev", "go"),
+		" ", CustomEmoji("5368324170671202286", "👍"),
+		Spoiler(""), 42,
+	)
+
+	assert.Equal(t, "👋 Hi, Jon! bold nested 🎉 tail x := 1 fmt.Println() go 👍42", f.Text)
+	assert.Equal(t, Entities{
+		{Type: EntityTMention, Offset: 7, Length: 3, User: user},
+		{Type: EntityBold, Offset: 12, Length: 19},
+		{Type: EntityItalic, Offset: 17, Length: 9},
+		{Type: EntityCode, Offset: 32, Length: 6},
+		{Type: EntityCodeBlock, Offset: 39, Length: 13, Language: "go"},
+		{Type: EntityTextLink, Offset: 53, Length: 2, URL: "https://go.dev"},
+		{Type: EntityCustomEmoji, Offset: 56, Length: 2, CustomEmoji: "5368324170671202286"},
+	}, f.Entities)
+
+	for _, e := range f.Entities {
+		m := &Message{Text: f.Text}
+		assert.NotEmpty(t, m.EntityText(e))
+	}
+	assert.Equal(t, "nested 🎉", (&Message{Text: f.Text}).EntityText(f.Entities[2]))
+
+	var tb TextBuilder
+	tb.Add("a", Blockquote("b"))
+	assert.Equal(t, 2, tb.Len())
+	assert.Equal(t, Entities{{Type: EntityBlockquote, Offset: 1, Length: 1}}, tb.Entities())
+}
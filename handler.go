@@ -1,5 +1,7 @@
 package telebot
 
+import "time"
+
 // Handler is a struct that holds all the information about a handler.
 type Handler struct {
 	synchronous bool
@@ -8,6 +10,9 @@ type Handler struct {
 
 	onError func(error, Context)
 
+	// albums buffers media groups for the OnAlbum endpoint.
+	albums *albumCollector
+
 	// handlers is a map of all the handlers.
 	handlers map[string]HandlerFunc
 	// middleware is a main chain of middleware functions.
@@ -21,6 +26,7 @@ func NewHandler(settings HandlerSettings) *Handler {
 		parseMode:   settings.ParseMode,
 		onError:     settings.OnError,
 
+		albums:   newAlbumCollector(settings.AlbumTimeout),
 		handlers: make(map[string]HandlerFunc),
 	}
 }
@@ -45,6 +51,12 @@ type HandlerSettings struct {
 	// resulted from the handler. It is used as post-middleware function.
 	// Notice that context can be nil.
 	OnError func(error, Context)
+
+	// AlbumTimeout is a quiet period the messages of a media group are
+	// collected for before they are delivered to the OnAlbum handler.
+	// Defaults to DefaultAlbumTimeout. The album handler is always
+	// called after the timeout, regardless of Synchronous.
+	AlbumTimeout time.Duration
 }
 
 // Handle lets you set the handler for some command name or
@@ -2,7 +2,13 @@ package telebot
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
 )
 
 // Query is an incoming inline query. When the user sends
@@ -118,6 +124,70 @@ func (ir *InlineResult) MessageSig() (string, int64) {
 	return ir.MessageID, 0
 }
 
+// resultMetas keeps the metadata of the served inline results
+// until the chosen inline results arrive. The results are keyed
+// by the user they were served to, as the IDs are only unique
+// within a single answer.
+type resultMetas struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[string]resultMeta
+	swept time.Time
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+type resultMeta struct {
+	value   interface{}
+	expires time.Time
+}
+
+func newResultMetas(ttl time.Duration) *resultMetas {
+	return &resultMetas{ttl: ttl, items: make(map[string]resultMeta), now: time.Now}
+}
+
+func (s *resultMetas) remember(user *User, id string, meta interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.swept) > s.ttl {
+		for k, v := range s.items {
+			if !now.Before(v.expires) {
+				delete(s.items, k)
+			}
+		}
+		s.swept = now
+	}
+
+	s.items[resultKey(user, id)] = resultMeta{value: meta, expires: now.Add(s.ttl)}
+}
+
+func (s *resultMetas) lookup(user *User, id string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta, ok := s.items[resultKey(user, id)]
+	if !ok || !s.now().Before(meta.expires) {
+		return nil, false
+	}
+	return meta.value, true
+}
+
+func resultKey(user *User, id string) string {
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+	return strconv.FormatInt(userID, 10) + "|" + id
+}
+
+// metaResult is implemented by the results carrying metadata.
+type metaResult interface {
+	ResultMeta() interface{}
+}
+
 // Result represents one result of an inline query.
 type Result interface {
 	ResultID() string
@@ -132,50 +202,170 @@ type Result interface {
 type Results []Result
 
 // MarshalJSON makes sure IQRs have proper IDs and Type variables set.
+// The missing IDs are the FNV-1 hashes of the results, so the same
+// result gets the same ID in every answer.
 func (results Results) MarshalJSON() ([]byte, error) {
+	ids := make(map[string]bool, len(results))
+	for _, result := range results {
+		if err := inferIQR(result); err != nil {
+			return nil, err
+		}
+		if result.ResultID() != "" {
+			ids[result.ResultID()] = true
+		}
+	}
+
 	for i, result := range results {
-		if result.ResultID() == "" {
-			result.SetResultID(fmt.Sprintf("%d", &results[i]))
+		if result.ResultID() != "" {
+			continue
 		}
-		if err := inferIQR(result); err != nil {
+
+		data, err := json.Marshal(result)
+		if err != nil {
 			return nil, err
 		}
+
+		id := resultHash(data)
+		if ids[id] {
+			// Identical results must still have unique IDs.
+			id = resultHash(append(data, strconv.Itoa(i)...))
+		}
+
+		ids[id] = true
+		result.SetResultID(id)
 	}
 
 	return json.Marshal([]Result(results))
 }
 
-func inferIQR(result Result) error {
-	switch r := result.(type) {
-	case *ArticleResult:
-		r.Type = "article"
-	case *AudioResult:
-		r.Type = "audio"
-	case *ContactResult:
-		r.Type = "contact"
-	case *DocumentResult:
-		r.Type = "document"
-	case *GifResult:
-		r.Type = "gif"
-	case *LocationResult:
-		r.Type = "location"
-	case *Mpeg4GifResult:
-		r.Type = "mpeg4_gif"
-	case *PhotoResult:
-		r.Type = "photo"
-	case *VenueResult:
-		r.Type = "venue"
-	case *VideoResult:
-		r.Type = "video"
-	case *VoiceResult:
-		r.Type = "voice"
-	case *StickerResult:
-		r.Type = "sticker"
-	case *GameResult:
-		r.Type = "game"
-	default:
-		return fmt.Errorf("telebot: result %v is not supported", result)
+func resultHash(data []byte) string {
+	h := fnv.New64()
+	h.Write(data)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+var (
+	ErrUnsupportedResult     = errors.New("telebot: result is not supported")
+	ErrResultFieldRequired   = errors.New("telebot: required result field is empty")
+	ErrResultFieldsExclusive = errors.New("telebot: result fields are mutually exclusive")
+	ErrResultReplyKeyboard   = errors.New("telebot: result reply markup must be an inline keyboard")
+)
+
+// ResultError describes the invalid result of the answer.
+type ResultError struct {
+	Index int
+	Type  string
+	Err   error
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%s (result %d of type %s)", e.Err, e.Index, e.Type)
+}
+
+// Unwrap returns the reason of the error, e.g. ErrResultFieldRequired.
+func (e *ResultError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the required and mutually exclusive fields of the
+// results, so the mistakes are reported before the request. Answer and
+// AnswerWebApp call it on their own. The results implementing
+// Validate() error are checked with it, including the custom ones.
+func (results Results) Validate() error {
+	for i, result := range results {
+		if err := inferIQR(result); err != nil {
+			return err
+		}
+
+		v, ok := result.(interface{ Validate() error })
+		if !ok {
+			continue
+		}
+		if err := v.Validate(); err != nil {
+			typ, _ := resultType(result)
+			return &ResultError{Index: i, Type: typ, Err: err}
+		}
 	}
+	return nil
+}
+
+var (
+	resultTypesMu sync.RWMutex
+	resultTypes   = make(map[reflect.Type]string)
+	resultNames   = make(map[string]reflect.Type)
+)
 
+func init() {
+	RegisterResult("article", &ArticleResult{})
+	RegisterResult("audio", &AudioResult{})
+	RegisterResult("contact", &ContactResult{})
+	RegisterResult("document", &DocumentResult{})
+	RegisterResult("gif", &GifResult{})
+	RegisterResult("location", &LocationResult{})
+	RegisterResult("mpeg4_gif", &Mpeg4GifResult{})
+	RegisterResult("photo", &PhotoResult{})
+	RegisterResult("venue", &VenueResult{})
+	RegisterResult("video", &VideoResult{})
+	RegisterResult("voice", &VoiceResult{})
+	RegisterResult("sticker", &StickerResult{})
+	RegisterResult("game", &GameResult{})
+}
+
+// RegisterResult registers the custom Result implementation with its
+// Telegram type, so it can be used in the answers. If the result embeds
+// ResultBase, its Type is set automatically. Implement Validate() error
+// to check the result before sending.
+//
+// Example:
+//
+//	type CachedAudioResult struct {
+//		tele.ResultBase
+//		Cache string `json:"audio_file_id"`
+//	}
+//
+//	tele.RegisterResult("audio", &CachedAudioResult{})
+func RegisterResult(typ string, r Result) {
+	resultTypesMu.Lock()
+	defer resultTypesMu.Unlock()
+
+	t := reflect.TypeOf(r)
+	resultTypes[t] = typ
+	resultNames[typ] = t
+}
+
+// NewResult returns a new empty result of the type registered
+// last with the Telegram type, or nil if there is none.
+func NewResult(typ string) Result {
+	resultTypesMu.RLock()
+	t, ok := resultNames[typ]
+	resultTypesMu.RUnlock()
+
+	if !ok || t.Kind() != reflect.Ptr {
+		return nil
+	}
+	return reflect.New(t.Elem()).Interface().(Result)
+}
+
+func resultType(result Result) (string, bool) {
+	resultTypesMu.RLock()
+	defer resultTypesMu.RUnlock()
+
+	typ, ok := resultTypes[reflect.TypeOf(result)]
+	return typ, ok
+}
+
+// typedResult is implemented by the results embedding ResultBase.
+type typedResult interface {
+	setType(string)
+}
+
+func inferIQR(result Result) error {
+	typ, ok := resultType(result)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedResult, result)
+	}
+	if r, ok := result.(typedResult); ok {
+		r.setType(typ)
+	}
 	return nil
 }
@@ -0,0 +1,190 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxInlineResults is the maximum number of results
+// in a single answer to an inline query.
+const MaxInlineResults = 50
+
+// ErrNoResultLoader is returned when the inline pager has no loader set.
+var ErrNoResultLoader = errors.New("telebot: inline pager has no result loader")
+
+// ResultLoader returns all the results for the inline query.
+type ResultLoader func(c Context, q *Query) (Results, error)
+
+// InlinePager answers the inline queries with the results of Load
+// page by page, handling the query offsets. The results are cached
+// per query text for CacheTime, so Load isn't called on each page.
+//
+// Example:
+//
+//	pager := &tele.InlinePager{
+//		CacheTime: 60,
+//		Load: func(c tele.Context, q *tele.Query) (tele.Results, error) {
+//			return searchArticles(q.Text)
+//		},
+//	}
+//
+//	handler.Handle(tele.OnQuery, pager.Answer)
+type InlinePager struct {
+	// Load returns all the results for the query.
+	Load ResultLoader
+
+	// Limit is the number of results per page,
+	// defaults to and is capped at MaxInlineResults.
+	Limit int
+
+	// CacheTime is the number of seconds the results are cached
+	// for, both by Telegram and by the pager itself.
+	CacheTime int
+
+	// Personal makes the results cached for each user separately.
+	Personal bool
+
+	// Button is shown above the results.
+	Button *QueryResponseButton
+
+	mu    sync.Mutex
+	cache map[string]cachedResults
+}
+
+type cachedResults struct {
+	results []json.RawMessage
+	expires time.Time
+
+	// ids and metas go along the results, metas
+	// are nil for the results with no metadata.
+	ids   []string
+	metas []interface{}
+}
+
+// Answer answers the current inline query with the page of results
+// at its offset. The offset past the end gets an empty page.
+func (p *InlinePager) Answer(c Context) error {
+	q := c.Query()
+	if q == nil {
+		return errors.New("telebot: context inline query is nil")
+	}
+
+	cached, err := p.results(c, q)
+	if err != nil {
+		return err
+	}
+	results := cached.results
+
+	limit := p.Limit
+	if limit <= 0 || limit > MaxInlineResults {
+		limit = MaxInlineResults
+	}
+
+	offset, _ := strconv.Atoi(q.Offset)
+	if offset < 0 || offset > len(results) {
+		offset = len(results)
+	}
+
+	end, next := offset+limit, ""
+	if end < len(results) {
+		next = strconv.Itoa(end)
+	} else {
+		end = len(results)
+	}
+
+	params := map[string]interface{}{
+		"inline_query_id": q.ID,
+		"results":         results[offset:end],
+		"cache_time":      p.CacheTime,
+		"is_personal":     p.Personal,
+		"next_offset":     next,
+	}
+	if p.Button != nil {
+		params["button"] = p.Button
+	}
+
+	if _, err := c.Bot().Raw("answerInlineQuery", params); err != nil {
+		return err
+	}
+
+	for i := offset; i < end; i++ {
+		if meta := cached.metas[i]; meta != nil {
+			c.Bot().RememberResult(q.Sender, cached.ids[i], meta)
+		}
+	}
+	return nil
+}
+
+// results returns the cached results of the query or loads them.
+// The results are kept marshalled, so the cached ones are never
+// processed again and keep their IDs.
+func (p *InlinePager) results(c Context, q *Query) (cachedResults, error) {
+	if p.Load == nil {
+		return cachedResults{}, ErrNoResultLoader
+	}
+
+	key := q.Text
+	if p.Personal && q.Sender != nil {
+		key = strconv.FormatInt(q.Sender.ID, 10) + "|" + key
+	}
+
+	now := time.Now()
+
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	p.mu.Unlock()
+
+	if ok && now.Before(cached.expires) {
+		return cached, nil
+	}
+
+	results, err := p.Load(c, q)
+	if err != nil {
+		return cachedResults{}, err
+	}
+	if err := results.Validate(); err != nil {
+		return cachedResults{}, err
+	}
+	for _, result := range results {
+		result.Process(c.Bot())
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return cachedResults{}, wrapError(err)
+	}
+
+	cached = cachedResults{
+		expires: now.Add(time.Duration(p.CacheTime) * time.Second),
+		ids:     make([]string, len(results)),
+		metas:   make([]interface{}, len(results)),
+	}
+	if err := json.Unmarshal(data, &cached.results); err != nil {
+		return cachedResults{}, wrapError(err)
+	}
+	for i, result := range results {
+		cached.ids[i] = result.ResultID()
+		if r, ok := result.(metaResult); ok {
+			cached.metas[i] = r.ResultMeta()
+		}
+	}
+
+	if p.CacheTime > 0 {
+		p.mu.Lock()
+		if p.cache == nil {
+			p.cache = make(map[string]cachedResults)
+		}
+		for k, v := range p.cache {
+			if !now.Before(v.expires) {
+				delete(p.cache, k)
+			}
+		}
+		p.cache[key] = cached
+		p.mu.Unlock()
+	}
+
+	return cached, nil
+}
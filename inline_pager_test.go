@@ -0,0 +1,95 @@
+package telebot
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlinePager(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	h := NewHandler(HandlerSettings{Synchronous: true, ParseMode: ModeHTML})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	loads := 0
+	pager := &InlinePager{
+		CacheTime: 60,
+		Personal:  true,
+		Load: func(c Context, q *Query) (Results, error) {
+			loads++
+			var results Results
+			for i := 0; i < 120; i++ {
+				results = append(results, &ArticleResult{
+					ResultBase: ResultBase{Meta: i},
+					Title:      q.Text + strconv.Itoa(i),
+					Text:       "text",
+				})
+			}
+			return results, nil
+		},
+	}
+	h.Handle(OnQuery, pager.Answer)
+
+	var (
+		params  map[string]string
+		results []map[string]interface{}
+	)
+	query := func(text, offset string) {
+		b.ProcessUpdate(Update{Query: &Query{ID: "1", Sender: &User{ID: 1}, Text: text, Offset: offset}})
+		params, results = api.Last().Params, nil
+		require.NoError(t, json.Unmarshal([]byte(params["results"]), &results))
+	}
+
+	query("go", "")
+	require.Len(t, results, MaxInlineResults)
+	assert.Equal(t, "1", params["inline_query_id"])
+	assert.Equal(t, "60", params["cache_time"])
+	assert.Equal(t, "true", params["is_personal"])
+	assert.Equal(t, "50", params["next_offset"])
+	assert.Equal(t, "go0", results[0]["title"])
+	assert.Equal(t, "HTML", results[0]["parse_mode"])
+	firstID := results[0]["id"]
+
+	query("go", "100")
+	require.Len(t, results, 20)
+	assert.Equal(t, "go100", results[0]["title"])
+	assert.Equal(t, "", params["next_offset"])
+	assert.Equal(t, 1, loads)
+
+	// Only the metadata of the results sent is remembered.
+	b.results = newResultMetas(time.Minute)
+	query("go", "100")
+	meta, ok := b.ResultMeta(&User{ID: 1}, results[0]["id"].(string))
+	assert.True(t, ok)
+	assert.Equal(t, 100, meta)
+	_, ok = b.ResultMeta(&User{ID: 1}, firstID.(string))
+	assert.False(t, ok)
+
+	query("go", "1000")
+	assert.Empty(t, results)
+
+	query("rust", "")
+	assert.Equal(t, "rust0", results[0]["title"])
+	assert.Equal(t, 2, loads)
+
+	// The IDs stay the same once the cache expires.
+	pager.cache = nil
+	query("go", "")
+	assert.Equal(t, 3, loads)
+	assert.Equal(t, firstID, results[0]["id"])
+
+	pager.Limit = 10
+	query("go", "10")
+	require.Len(t, results, 10)
+	assert.Equal(t, "go10", results[0]["title"])
+	assert.Equal(t, "20", params["next_offset"])
+
+	c := b.NewContext(Update{Query: &Query{ID: "2", Text: "go"}})
+	assert.Equal(t, ErrNoResultLoader, (&InlinePager{}).Answer(c))
+}
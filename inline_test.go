@@ -0,0 +1,184 @@
+package telebot
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultsMarshal(t *testing.T) {
+	build := func() Results {
+		return Results{
+			&ArticleResult{Title: "a", Text: "a"},
+			&ArticleResult{Title: "b", Text: "b"},
+			&ArticleResult{Title: "a", Text: "a"},
+			&PhotoResult{ResultBase: ResultBase{ID: "photo"}, URL: "https://example.com/p.jpg"},
+		}
+	}
+
+	first, second := build(), build()
+	data, err := json.Marshal(first)
+	require.NoError(t, err)
+	_, err = json.Marshal(second)
+	require.NoError(t, err)
+
+	ids := make(map[string]bool)
+	for i := range first {
+		assert.Equal(t, first[i].ResultID(), second[i].ResultID())
+		assert.NotEmpty(t, first[i].ResultID())
+		ids[first[i].ResultID()] = true
+	}
+	assert.Len(t, ids, 4)
+	assert.Equal(t, "photo", first[3].ResultID())
+	assert.Contains(t, string(data), `"type":"article"`)
+
+	_, err = json.Marshal(Results{nil})
+	assert.Error(t, err)
+}
+
+func TestResultMeta(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	h := NewHandler(HandlerSettings{Synchronous: true})
+	b, err := NewBot(Settings{
+		URL:             api.URL(),
+		Offline:         true,
+		Handler:         h,
+		InlineResultTTL: time.Minute,
+	})
+	require.NoError(t, err)
+
+	clock := time.Now()
+	b.results.now = func() time.Time { return clock }
+
+	markup := &ReplyMarkup{}
+	markup.Inline(markup.Row(markup.Data("Vote", "vote")))
+
+	results := Results{
+		&ArticleResult{ResultBase: ResultBase{Meta: "poll:1", ReplyMarkup: markup}, Title: "Poll", Text: "Poll"},
+		&ArticleResult{Title: "Plain", Text: "Plain"},
+	}
+	user := &User{ID: 1}
+	require.NoError(t, b.Answer(&Query{ID: "1", Sender: user}, &QueryResponse{Results: results}))
+
+	var (
+		meta    interface{}
+		editErr error
+	)
+	h.Handle(OnInlineResult, func(c Context) error {
+		meta = c.ResultMeta()
+		editErr = c.EditReplyMarkup(nil)
+		return nil
+	})
+
+	chosen := func(sender *User, id, msgID string) {
+		b.ProcessUpdate(Update{InlineResult: &InlineResult{Sender: sender, ResultID: id, MessageID: msgID}})
+	}
+
+	chosen(user, results[0].ResultID(), "inline-1")
+	assert.Equal(t, "poll:1", meta)
+	require.NoError(t, editErr)
+	assert.Equal(t, "inline-1", api.Last().Params["inline_message_id"])
+
+	// The same ID served to another user has no metadata.
+	chosen(&User{ID: 2}, results[0].ResultID(), "inline-1")
+	assert.Nil(t, meta)
+
+	chosen(user, results[1].ResultID(), "")
+	assert.Nil(t, meta)
+	assert.Equal(t, ErrNoInlineMessage, editErr)
+
+	clock = clock.Add(time.Minute)
+	chosen(user, results[0].ResultID(), "inline-1")
+	assert.Nil(t, meta)
+
+	pager := &InlinePager{Load: func(c Context, q *Query) (Results, error) {
+		return Results{&ArticleResult{ResultBase: ResultBase{ID: "a", Meta: 42}, Title: "A", Text: "A"}}, nil
+	}}
+	h.Handle(OnQuery, pager.Answer)
+	b.ProcessUpdate(Update{Query: &Query{ID: "2", Sender: user, Text: "a"}})
+
+	chosen(user, "a", "inline-2")
+	assert.Equal(t, 42, meta)
+}
+
+type testCachedAudio struct {
+	ResultBase
+	Cache string `json:"audio_file_id"`
+}
+
+func (r *testCachedAudio) Validate() error {
+	if r.Cache == "" {
+		return requiredField("audio_file_id")
+	}
+	return nil
+}
+
+func TestResultsValidate(t *testing.T) {
+	_, err := json.Marshal(Results{&testCachedAudio{}})
+	assert.ErrorIs(t, err, ErrUnsupportedResult)
+
+	RegisterResult("cached_audio_test", &testCachedAudio{})
+
+	data, err := json.Marshal(Results{&testCachedAudio{Cache: "file"}})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"cached_audio_test"`)
+	assert.IsType(t, &testCachedAudio{}, NewResult("cached_audio_test"))
+	assert.Nil(t, NewResult("unknown"))
+
+	markup := &ReplyMarkup{}
+	markup.Reply(markup.Row(markup.Text("text")))
+
+	tests := []struct {
+		result Result
+		err    error
+	}{
+		{&ArticleResult{Title: "a", Text: "a"}, nil},
+		{&ArticleResult{Text: "a"}, ErrResultFieldRequired},
+		{&ArticleResult{Title: "a"}, ErrResultFieldRequired},
+		{&ArticleResult{Title: "a", Text: "a", ResultBase: ResultBase{Content: &InputTextMessageContent{Text: "a"}}}, ErrResultFieldsExclusive},
+		{&ArticleResult{Title: "a", Text: "a", ResultBase: ResultBase{ReplyMarkup: markup}}, ErrResultReplyKeyboard},
+		{&PhotoResult{Cache: "file"}, nil},
+		{&PhotoResult{}, ErrResultFieldRequired},
+		{&PhotoResult{URL: "https://example.com/p.jpg", Cache: "file"}, nil},
+		{&VideoResult{URL: "https://example.com/v.mp4", Cache: "file", Title: "v"}, nil},
+		{&PhotoResult{URL: "https://example.com/p.jpg"}, ErrResultFieldRequired},
+		{&VideoResult{URL: "https://example.com/v.mp4", MIME: "video/mp4", Title: "v"}, ErrResultFieldRequired},
+		{&VideoResult{Cache: "file", Title: "v"}, nil},
+		{&AudioResult{Cache: "file"}, nil},
+		{&StickerResult{}, ErrResultFieldRequired},
+		{&testCachedAudio{}, ErrResultFieldRequired},
+	}
+	for i, tt := range tests {
+		err := Results{tt.result}.Validate()
+		if tt.err == nil {
+			assert.NoError(t, err, i)
+			continue
+		}
+
+		assert.ErrorIs(t, err, tt.err, i)
+		var rerr *ResultError
+		if assert.ErrorAs(t, err, &rerr, i) {
+			assert.Equal(t, 0, rerr.Index)
+		}
+	}
+
+	err = Results{&ArticleResult{Title: "a", Text: "a"}, &PhotoResult{URL: "https://example.com/p.jpg"}}.Validate()
+	assert.EqualError(t, err, "telebot: required result field is empty: thumbnail_url (result 1 of type photo)")
+
+	api := newTestAPI(t, nil)
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	err = b.Answer(&Query{ID: "1"}, &QueryResponse{Results: Results{&VideoResult{Title: "v"}}})
+	assert.ErrorIs(t, err, ErrResultFieldRequired)
+	_, err = b.AnswerWebApp(&Query{ID: "1"}, &ContactResult{FirstName: "A"})
+	assert.ErrorIs(t, err, ErrResultFieldRequired)
+	assert.Empty(t, api.Requests())
+
+	require.NoError(t, b.Answer(&Query{ID: "1"}, &QueryResponse{Results: Results{&testCachedAudio{Cache: "file"}}}))
+	assert.Len(t, api.Requests(), 1)
+}
@@ -1,9 +1,11 @@
 package telebot
 
+import "fmt"
+
 // ResultBase must be embedded into all IQRs.
 type ResultBase struct {
 	// Unique identifier for this result, 1-64 Bytes.
-	// If left unspecified, a 64-bit FNV-1 hash will be calculated
+	// If left unspecified, a 64-bit FNV-1 hash of the result will be calculated.
 	ID string `json:"id"`
 
 	// Ignore. This field gets set automatically.
@@ -18,6 +20,10 @@ type ResultBase struct {
 
 	// Optional. Inline keyboard attached to the message.
 	ReplyMarkup *ReplyMarkup `json:"reply_markup,omitempty"`
+
+	// Optional. Meta is remembered by the bot once the result is served,
+	// and returned by Context.ResultMeta when the user chooses it.
+	Meta interface{} `json:"-"`
 }
 
 // ResultID returns ResultBase.ID.
@@ -45,6 +51,15 @@ func (r *ResultBase) SetReplyMarkup(markup *ReplyMarkup) {
 	r.ReplyMarkup = markup
 }
 
+func (r *ResultBase) setType(typ string) {
+	r.Type = typ
+}
+
+// ResultMeta returns ResultBase.Meta.
+func (r *ResultBase) ResultMeta() interface{} {
+	return r.Meta
+}
+
 func (r *ResultBase) Process(b *Bot) {
 	if r.ParseMode == ModeDefault {
 		r.ParseMode = b.handler.parseMode
@@ -56,7 +71,10 @@ func (r *ResultBase) Process(b *Bot) {
 		}
 	}
 	if r.ReplyMarkup != nil {
-		processButtons(r.ReplyMarkup.InlineKeyboard)
+		// The markup may be shared between the results.
+		markup := *r.ReplyMarkup
+		markup.InlineKeyboard = processButtons(markup.InlineKeyboard)
+		r.ReplyMarkup = &markup
 	}
 }
 
@@ -381,3 +399,186 @@ type StickerResult struct {
 	// If Cache != "", it'll be used instead
 	Cache string `json:"sticker_file_id,omitempty"`
 }
+
+// Validate checks the required fields of the result.
+func (r *GameResult) Validate() error {
+	if r.ShortName == "" {
+		return requiredField("game_short_name")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required and mutually exclusive fields of the result.
+func (r *ArticleResult) Validate() error {
+	if r.Title == "" {
+		return requiredField("title")
+	}
+	if err := oneOf("message_text", r.Text != "", "input_message_content", r.Content != nil); err != nil {
+		return err
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *AudioResult) Validate() error {
+	if err := urlOrCache("audio_url", r.URL, "audio_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Cache == "" && r.Title == "" {
+		return requiredField("title")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *ContactResult) Validate() error {
+	if r.PhoneNumber == "" {
+		return requiredField("phone_number")
+	}
+	if r.FirstName == "" {
+		return requiredField("first_name")
+	}
+	if len(r.VCard) > MaxVCardSize {
+		return ErrTooLongVCard
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *DocumentResult) Validate() error {
+	if err := urlOrCache("document_url", r.URL, "document_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Title == "" {
+		return requiredField("title")
+	}
+	if r.Cache == "" && r.MIME == "" {
+		return requiredField("mime_type")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *GifResult) Validate() error {
+	if err := urlOrCache("gif_url", r.URL, "gif_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Cache == "" && r.ThumbURL == "" {
+		return requiredField("thumbnail_url")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *LocationResult) Validate() error {
+	if r.Title == "" {
+		return requiredField("title")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *Mpeg4GifResult) Validate() error {
+	if err := urlOrCache("mpeg4_url", r.URL, "mpeg4_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Cache == "" && r.ThumbURL == "" {
+		return requiredField("thumbnail_url")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *PhotoResult) Validate() error {
+	if err := urlOrCache("photo_url", r.URL, "photo_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Cache == "" && r.ThumbURL == "" {
+		return requiredField("thumbnail_url")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *VenueResult) Validate() error {
+	if r.Title == "" {
+		return requiredField("title")
+	}
+	if r.Address == "" {
+		return requiredField("address")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *VideoResult) Validate() error {
+	if err := urlOrCache("video_url", r.URL, "video_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Title == "" {
+		return requiredField("title")
+	}
+	if r.Cache == "" {
+		if r.MIME == "" {
+			return requiredField("mime_type")
+		}
+		if r.ThumbURL == "" {
+			return requiredField("thumbnail_url")
+		}
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *VoiceResult) Validate() error {
+	if err := urlOrCache("voice_url", r.URL, "voice_file_id", r.Cache); err != nil {
+		return err
+	}
+	if r.Title == "" {
+		return requiredField("title")
+	}
+	return r.ResultBase.validate()
+}
+
+// Validate checks the required fields of the result.
+func (r *StickerResult) Validate() error {
+	if r.Cache == "" {
+		return requiredField("sticker_file_id")
+	}
+	return r.ResultBase.validate()
+}
+
+// validate checks the reply markup of the result, which must be inline.
+func (r *ResultBase) validate() error {
+	if r.ReplyMarkup == nil {
+		return nil
+	}
+	if len(r.ReplyMarkup.ReplyKeyboard) > 0 || r.ReplyMarkup.ForceReply || r.ReplyMarkup.RemoveKeyboard {
+		return ErrResultReplyKeyboard
+	}
+	return r.ReplyMarkup.Validate()
+}
+
+func requiredField(name string) error {
+	return fmt.Errorf("%w: %s", ErrResultFieldRequired, name)
+}
+
+// urlOrCache checks that the file is given by the URL or by the cached
+// file ID. If both are set, the cached file is used instead of the URL.
+func urlOrCache(urlField, url, cacheField, cache string) error {
+	if url == "" && cache == "" {
+		return fmt.Errorf("%w: %s or %s", ErrResultFieldRequired, urlField, cacheField)
+	}
+	return nil
+}
+
+// oneOf checks that exactly one of the two fields is set.
+func oneOf(a string, aSet bool, b string, bSet bool) error {
+	switch {
+	case aSet && bSet:
+		return fmt.Errorf("%w: %s and %s", ErrResultFieldsExclusive, a, b)
+	case !aSet && !bSet:
+		return fmt.Errorf("%w: %s or %s", ErrResultFieldRequired, a, b)
+	}
+	return nil
+}
@@ -549,8 +549,17 @@ func (lt *Layout) ResultLocale(locale, k string, args ...interface{}) tele.Resul
 			log.Println("telebot/layout:", err)
 		}
 	default:
-		log.Println("telebot/layout: unsupported inline result type")
-		return nil
+		// The custom result types registered with tele.RegisterResult.
+		r = tele.NewResult(base.Type)
+		if r == nil {
+			log.Println("telebot/layout: unsupported inline result type")
+			return nil
+		}
+		if err := yaml.Unmarshal(data, r); err != nil {
+			log.Println("telebot/layout:", err)
+		}
+		r.SetResultID(base.ID)
+		r.SetParseMode(base.ParseMode)
 	}
 
 	if base.Content != nil {
@@ -2,7 +2,9 @@ package telebot
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -365,3 +367,172 @@ const (
 	MenuButtonCommands MenuButtonType = "commands"
 	MenuButtonWebApp   MenuButtonType = "web_app"
 )
+
+// Telegram limits of the keyboards.
+const (
+	MaxCallbackData        = 64
+	MaxInlineButtonsPerRow = 8
+	MaxInlineButtons       = 100
+	MaxReplyButtonsPerRow  = 12
+	MaxReplyButtons        = 300
+)
+
+var (
+	ErrMixedKeyboards      = errors.New("telebot: inline keyboard can't be combined with the reply keyboard, force reply or keyboard removal")
+	ErrTooManyButtons      = errors.New("telebot: too many buttons in the keyboard")
+	ErrTooManyRowButtons   = errors.New("telebot: too many buttons in the row")
+	ErrEmptyButtonText     = errors.New("telebot: button text is empty")
+	ErrButtonAction        = errors.New("telebot: inline button can't have more than one action")
+	ErrReplyButtonRequest  = errors.New("telebot: reply button can request only one thing")
+	ErrTooLongCallbackData = errors.New("telebot: callback data exceeds 64 bytes")
+	ErrBadButtonURL        = errors.New("telebot: button url scheme must be http, https or tg")
+	ErrInsecureButtonURL   = errors.New("telebot: button url must be https")
+)
+
+// ButtonError describes the invalid button of the keyboard.
+type ButtonError struct {
+	Row, Column int
+	Err         error
+}
+
+func (e *ButtonError) Error() string {
+	return fmt.Sprintf("%s (button row %d column %d)", e.Err, e.Row, e.Column)
+}
+
+// Unwrap returns the reason of the error, e.g. ErrTooLongCallbackData.
+func (e *ButtonError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the keyboard against the Telegram limits, so the
+// mistakes are reported before the request. Send and Edit methods call
+// it on their own. The returned error is either one of the ErrMixedKeyboards,
+// ErrTooManyButtons, or a *ButtonError pointing to the invalid button.
+func (r *ReplyMarkup) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	// The reply keyboard options, like ResizeKeyboard, are simply
+	// ignored along with the inline keyboard, so only the conflicting
+	// kinds of the markup are rejected.
+	if len(r.InlineKeyboard) > 0 && (len(r.ReplyKeyboard) > 0 || r.ForceReply || r.RemoveKeyboard) {
+		return ErrMixedKeyboards
+	}
+
+	total := 0
+	for i, row := range r.InlineKeyboard {
+		if len(row) > MaxInlineButtonsPerRow {
+			return &ButtonError{Row: i, Column: MaxInlineButtonsPerRow, Err: ErrTooManyRowButtons}
+		}
+		for j := range row {
+			if err := row[j].validate(); err != nil {
+				return &ButtonError{Row: i, Column: j, Err: err}
+			}
+		}
+		if total += len(row); total > MaxInlineButtons {
+			return ErrTooManyButtons
+		}
+	}
+
+	total = 0
+	for i, row := range r.ReplyKeyboard {
+		if len(row) > MaxReplyButtonsPerRow {
+			return &ButtonError{Row: i, Column: MaxReplyButtonsPerRow, Err: ErrTooManyRowButtons}
+		}
+		for j := range row {
+			if err := row[j].validate(); err != nil {
+				return &ButtonError{Row: i, Column: j, Err: err}
+			}
+		}
+		if total += len(row); total > MaxReplyButtons {
+			return ErrTooManyButtons
+		}
+	}
+
+	return nil
+}
+
+func (t *InlineButton) validate() error {
+	if t.Text == "" {
+		return ErrEmptyButtonText
+	}
+
+	// The button with no other action switches to the inline mode
+	// in the current chat with an empty query, see QueryChat.
+	actions := 0
+	for _, set := range []bool{
+		t.URL != "",
+		t.Unique != "" || t.Data != "",
+		t.InlineQuery != "",
+		t.InlineQueryChat != "",
+		t.InlineQueryChosenChat != nil,
+		t.Login != nil,
+		t.WebApp != nil,
+	} {
+		if set {
+			actions++
+		}
+	}
+	if actions > 1 {
+		return ErrButtonAction
+	}
+
+	if len(callbackData(t)) > MaxCallbackData {
+		return ErrTooLongCallbackData
+	}
+
+	if t.URL != "" {
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			return ErrBadButtonURL
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https", "tg":
+		default:
+			return ErrBadButtonURL
+		}
+	}
+	if t.Login != nil && !isHTTPS(t.Login.URL) {
+		return ErrInsecureButtonURL
+	}
+	if t.WebApp != nil && !isHTTPS(t.WebApp.URL) {
+		return ErrInsecureButtonURL
+	}
+
+	return nil
+}
+
+func (t *ReplyButton) validate() error {
+	if t.Text == "" {
+		return ErrEmptyButtonText
+	}
+
+	requests := 0
+	for _, set := range []bool{
+		t.Contact,
+		t.Location,
+		t.Poll != "",
+		t.User != nil,
+		t.Chat != nil,
+		t.WebApp != nil,
+	} {
+		if set {
+			requests++
+		}
+	}
+	if requests > 1 {
+		return ErrReplyButtonRequest
+	}
+
+	if t.WebApp != nil && !isHTTPS(t.WebApp.URL) {
+		return ErrInsecureButtonURL
+	}
+
+	return nil
+}
+
+func isHTTPS(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && strings.EqualFold(u.Scheme, "https")
+}
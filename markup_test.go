@@ -1,6 +1,8 @@
 package telebot
 
 import (
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -63,3 +65,137 @@ func TestOptions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte(`{"type":"quiz"}`), data)
 }
+
+func TestReplyMarkupValidate(t *testing.T) {
+	r := &ReplyMarkup{}
+	assert.NoError(t, (*ReplyMarkup)(nil).Validate())
+
+	r.Inline(r.Row(r.Data("T", "u", "1"), r.URL("T", "https://go.dev"), r.URL("T", "tg://resolve?domain=x")))
+	assert.NoError(t, r.Validate())
+
+	r.Inline(r.Row(r.QueryChat("T", ""), r.QueryChat("T", "q")))
+	assert.NoError(t, r.Validate())
+
+	check := func(target error, row ...Btn) {
+		r := &ReplyMarkup{}
+		r.Inline(row)
+
+		err := r.Validate()
+		assert.ErrorIs(t, err, target)
+
+		var berr *ButtonError
+		if assert.ErrorAs(t, err, &berr) {
+			assert.Equal(t, len(row)-1, berr.Column)
+		}
+	}
+
+	check(ErrTooLongCallbackData, r.Data("T", "u"), r.Data("T", "unique", strings.Repeat("x", 57)))
+	check(ErrButtonAction, Btn{Text: "T", URL: "https://go.dev", Data: "x"})
+	check(ErrEmptyButtonText, r.Data("", "u"))
+	check(ErrBadButtonURL, r.URL("T", "javascript:alert(1)"))
+	check(ErrInsecureButtonURL, r.WebApp("T", &WebApp{URL: "http://go.dev"}))
+
+	row := make(Row, MaxInlineButtonsPerRow+1)
+	for i := range row {
+		row[i] = r.Data("T", "u")
+	}
+	r = &ReplyMarkup{}
+	r.Inline(row)
+	assert.ErrorIs(t, r.Validate(), ErrTooManyRowButtons)
+
+	r = &ReplyMarkup{ResizeKeyboard: true, OneTimeKeyboard: true, Placeholder: "p"}
+	r.Inline(r.Row(r.Data("T", "u")))
+	assert.NoError(t, r.Validate())
+
+	r.RemoveKeyboard = true
+	assert.ErrorIs(t, r.Validate(), ErrMixedKeyboards)
+
+	r = &ReplyMarkup{}
+	r.Inline(r.Row(r.Data("T", "u")))
+	r.Reply(r.Row(r.Text("T")))
+	assert.ErrorIs(t, r.Validate(), ErrMixedKeyboards)
+
+	r = &ReplyMarkup{}
+	r.Reply(r.Row(Btn{Text: "T", Contact: true, Location: true}))
+	assert.ErrorIs(t, r.Validate(), ErrReplyButtonRequest)
+
+	b, err := NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+
+	r = &ReplyMarkup{}
+	r.Inline(r.Row(Btn{Text: "T", URL: "https://go.dev", Data: "x"}))
+	_, err = b.Send(&Chat{ID: 1}, "text", r)
+	assert.ErrorIs(t, err, ErrButtonAction)
+}
+
+func TestMarkupConcurrentSend(t *testing.T) {
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	r := &ReplyMarkup{}
+	r.Inline(r.Row(r.Data("T", "u", "1")))
+	opts := &SendOptions{ReplyMarkup: r}
+	msg := &Message{ID: 1, Chat: &Chat{ID: 1}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, err := b.Send(msg.Chat, "text", r)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := b.Send(msg.Chat, "text", opts)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := b.EditReplyMarkup(msg, r)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	requests := api.Requests()
+	require.Len(t, requests, 60)
+	for _, req := range requests {
+		assert.Contains(t, req.Params["reply_markup"], `"callback_data":"\fu|1"`)
+		assert.NotContains(t, req.Params["reply_markup"], `"unique"`)
+	}
+
+	assert.Equal(t, InlineButton{Unique: "u", Text: "T", Data: "1"}, r.InlineKeyboard[0][0])
+}
+
+func TestEmbedSendOptions(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+
+	params := make(map[string]string)
+	b.embedSendOptions(params, extractOptions([]interface{}{
+		&SendOptions{ReplyTo: &Message{ID: 5}, AllowWithoutReply: true},
+		NoPreview,
+	}))
+	assert.Equal(t, `{"message_id":5,"allow_sending_without_reply":true}`, params["reply_parameters"])
+	assert.Equal(t, `{"is_disabled":true}`, params["link_preview_options"])
+	assert.NotContains(t, params, "reply_to_message_id")
+	assert.NotContains(t, params, "disable_web_page_preview")
+
+	params = make(map[string]string)
+	b.embedSendOptions(params, extractOptions([]interface{}{
+		&SendOptions{ReplyTo: &Message{ID: 5}},
+		&ReplyParams{MessageID: 7, ChatID: 1, Quote: "q"},
+		&PreviewOptions{URL: "https://go.dev", AboveText: true},
+	}))
+	assert.Equal(t, `{"message_id":7,"chat_id":1,"quote":"q"}`, params["reply_parameters"])
+	assert.Equal(t, `{"url":"https://go.dev","show_above_text":true}`, params["link_preview_options"])
+
+	params = make(map[string]string)
+	b.embedSendOptions(params, &SendOptions{})
+	assert.Empty(t, params)
+}
@@ -328,6 +328,10 @@ type Contact struct {
 	// (Optional)
 	LastName string `json:"last_name"`
 	UserID   int64  `json:"user_id,omitempty"`
+
+	// (Optional) Additional data about the contact in the
+	// form of a vCard, 0-2048 bytes. See VCard.
+	VCard string `json:"vcard,omitempty"`
 }
 
 // Location object represents geographic position.
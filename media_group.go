@@ -0,0 +1,120 @@
+package telebot
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultAlbumTimeout is the default quiet period used to collect
+// the messages of a single media group before firing OnAlbum.
+const DefaultAlbumTimeout = time.Second
+
+// afterFunc is replaced in tests.
+var afterFunc = time.AfterFunc
+
+// albumCollector buffers messages sharing the same AlbumID until
+// no new items arrive within the timeout.
+type albumCollector struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	groups  map[string]*albumGroup
+}
+
+type albumGroup struct {
+	first    Update
+	messages []Message
+	timer    *time.Timer
+}
+
+func newAlbumCollector(timeout time.Duration) *albumCollector {
+	if timeout <= 0 {
+		timeout = DefaultAlbumTimeout
+	}
+	return &albumCollector{
+		timeout: timeout,
+		groups:  make(map[string]*albumGroup),
+	}
+}
+
+// add puts the message of the update into its media group and
+// (re)starts the quiet period timer. Once the timer fires, the whole
+// group is passed to the flush function. The kind separates the groups
+// of the messages and the channel posts.
+func (ac *albumCollector) add(u Update, m *Message, kind string, flush func(Update, []Message)) {
+	id := kind + "_" + m.Chat.Recipient() + "_" + m.AlbumID
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	// The timer that has already fired can't be restarted, as its
+	// group is being flushed. The message starts a new group then.
+	g, ok := ac.groups[id]
+	if ok && g.timer.Stop() {
+		g.messages = append(g.messages, *m)
+		g.timer.Reset(ac.timeout)
+		return
+	}
+
+	g = &albumGroup{first: u, messages: []Message{*m}}
+	g.timer = afterFunc(ac.timeout, func() {
+		ac.mu.Lock()
+		if ac.groups[id] == g {
+			delete(ac.groups, id)
+		}
+		msgs := g.messages
+		ac.mu.Unlock()
+
+		sort.SliceStable(msgs, func(i, j int) bool {
+			return msgs[i].ID < msgs[j].ID
+		})
+		flush(g.first, msgs)
+	})
+	ac.groups[id] = g
+}
+
+// handleAlbum buffers the message if it's a part of a media group
+// and the OnAlbum handler is registered. Returns false otherwise,
+// so the message is dispatched as a single media item.
+//
+// The messages and channel posts are collected separately, the edited
+// ones are dispatched to their own endpoints as usual. The handler is
+// called from the timer goroutine once the group is complete, so with
+// HandlerSettings.Synchronous it runs there rather than within
+// ProcessUpdate of any of the group's messages.
+func (b *Bot) handleAlbum(c Context) bool {
+	u := c.Update()
+
+	var m *Message
+	switch {
+	case u.Message != nil:
+		m = u.Message
+	case u.ChannelPost != nil:
+		m = u.ChannelPost
+	}
+	if m == nil || m.AlbumID == "" || m.Chat == nil {
+		return false
+	}
+
+	if _, ok := b.handler.handlers[OnAlbum]; !ok {
+		return false
+	}
+
+	kind := "message"
+	if u.ChannelPost != nil {
+		kind = "post"
+	}
+
+	b.handler.albums.add(u, m, kind, func(u Update, album []Message) {
+		b.handle(OnAlbum, withAlbum(b.NewContext(u), album))
+	})
+	return true
+}
+
+// withAlbum sets the media group of the native context.
+func withAlbum(c Context, album []Message) Context {
+	if nc, ok := c.(*nativeContext); ok {
+		nc.album = album
+	}
+	return c
+}
@@ -0,0 +1,204 @@
+package telebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAlbumTimers makes the album collectors wait for
+// the returned flush function instead of the quiet period.
+func fakeAlbumTimers(t *testing.T) (flush func()) {
+	fire := fakeAlbumTimersFire(t)
+	return func() { fire()() }
+}
+
+// fakeAlbumTimersFire is fakeAlbumTimers, which lets the fired timers
+// run their callbacks later, as if they were waiting on the collector.
+func fakeAlbumTimersFire(t *testing.T) (fire func() (run func())) {
+	type pending struct {
+		timer *time.Timer
+		f     func()
+	}
+
+	var timers []pending
+	afterFunc = func(d time.Duration, f func()) *time.Timer {
+		timer := time.NewTimer(time.Hour)
+		timers = append(timers, pending{timer, f})
+		return timer
+	}
+	t.Cleanup(func() { afterFunc = time.AfterFunc })
+
+	return func() func() {
+		fired := timers
+		timers = nil
+		for _, p := range fired {
+			p.timer.Stop()
+		}
+		return func() {
+			for _, p := range fired {
+				p.f()
+			}
+		}
+	}
+}
+
+func TestBotOnAlbum(t *testing.T) {
+	flush := fakeAlbumTimers(t)
+
+	b, err := NewBot(Settings{
+		Handler: NewHandler(HandlerSettings{Synchronous: true}),
+		Offline: true,
+	})
+	require.NoError(t, err)
+
+	albums := make(chan []Message, 1)
+	b.handler.Handle(OnAlbum, func(c Context) error {
+		albums <- c.Album()
+		return nil
+	})
+	b.handler.Handle(OnPhoto, func(c Context) error {
+		t.Error("OnPhoto must not be fired for album items")
+		return nil
+	})
+
+	chat := &Chat{ID: 1}
+	for _, id := range []int{3, 1, 2} {
+		b.ProcessUpdate(Update{Message: &Message{
+			ID:      id,
+			Chat:    chat,
+			AlbumID: "album",
+			Photo:   &Photo{},
+		}})
+	}
+	assert.Empty(t, albums)
+
+	flush()
+	require.Len(t, albums, 1)
+
+	album := <-albums
+	require.Len(t, album, 3)
+	for i, m := range album {
+		assert.Equal(t, i+1, m.ID)
+	}
+}
+
+func TestBotOnAlbumLateItem(t *testing.T) {
+	fire := fakeAlbumTimersFire(t)
+
+	b, err := NewBot(Settings{
+		Handler: NewHandler(HandlerSettings{Synchronous: true}),
+		Offline: true,
+	})
+	require.NoError(t, err)
+
+	albums := make(chan []Message, 3)
+	b.handler.Handle(OnAlbum, func(c Context) error {
+		albums <- c.Album()
+		return nil
+	})
+
+	chat := &Chat{ID: 1}
+	send := func(id int) {
+		b.ProcessUpdate(Update{Message: &Message{
+			ID:      id,
+			Chat:    chat,
+			AlbumID: "album",
+			Photo:   &Photo{},
+		}})
+	}
+
+	send(1)
+	send(2)
+
+	// The item arriving after the timer has fired,
+	// but before the album is flushed, starts a new one.
+	run := fire()
+	send(3)
+	run()
+	fire()()
+
+	require.Len(t, albums, 2)
+	assert.Len(t, <-albums, 2)
+	assert.Len(t, <-albums, 1)
+	assert.Empty(t, b.handler.albums.groups)
+}
+
+func TestBotOnAlbumFallback(t *testing.T) {
+	b, err := NewBot(Settings{
+		Handler: NewHandler(HandlerSettings{Synchronous: true}),
+		Offline: true,
+	})
+	require.NoError(t, err)
+
+	var fired int
+	b.handler.Handle(OnPhoto, func(c Context) error {
+		assert.Nil(t, c.Album())
+		fired++
+		return nil
+	})
+
+	chat := &Chat{ID: 1}
+	for id := 1; id <= 2; id++ {
+		b.ProcessUpdate(Update{Message: &Message{
+			ID:      id,
+			Chat:    chat,
+			AlbumID: "album",
+			Photo:   &Photo{},
+		}})
+	}
+
+	assert.Equal(t, 2, fired)
+}
+
+func TestBotOnAlbumChannelPost(t *testing.T) {
+	flush := fakeAlbumTimers(t)
+
+	b, err := NewBot(Settings{
+		Handler: NewHandler(HandlerSettings{Synchronous: true}),
+		Offline: true,
+	})
+	require.NoError(t, err)
+
+	albums := make(chan Context, 1)
+	b.handler.Handle(OnAlbum, func(c Context) error {
+		albums <- c
+		return nil
+	})
+	b.handler.Handle(OnChannelPost, func(c Context) error {
+		t.Error("OnChannelPost must not be fired for album items")
+		return nil
+	})
+
+	chat := &Chat{ID: -1, Type: ChatChannel}
+	for id := 1; id <= 2; id++ {
+		b.ProcessUpdate(Update{ChannelPost: &Message{
+			ID:      id,
+			Chat:    chat,
+			AlbumID: "album",
+			Photo:   &Photo{},
+		}})
+	}
+	var edited int
+	b.handler.Handle(OnEditedChannelPost, func(c Context) error {
+		edited++
+		return nil
+	})
+	b.ProcessUpdate(Update{EditedChannelPost: &Message{
+		ID:      1,
+		Chat:    chat,
+		AlbumID: "album",
+		Photo:   &Photo{},
+	}})
+	assert.Equal(t, 1, edited)
+
+	flush()
+	require.Len(t, albums, 1)
+
+	c := <-albums
+	assert.Equal(t, OnAlbum, c.Endpoint())
+	assert.NotNil(t, c.Update().ChannelPost)
+	assert.Len(t, c.Album(), 2)
+}
@@ -0,0 +1,339 @@
+package telebot
+
+import (
+	"errors"
+	"strings"
+)
+
+// Menu is a node of the nested inline menu: a message with the keyboard
+// of items, each of them opening a submenu, running an action, switching
+// a toggle or selecting a radio option. The navigation path is kept in
+// the callback data, and every step edits the current message in place.
+//
+// Example:
+//
+//	settings := tele.NewMenu("settings", "⚙️ Settings")
+//
+//	notify := settings.Submenu("notify", "🔔 Notifications", "Notification settings")
+//	notify.Toggle("sound", "Sound", getSound, setSound)
+//	notify.Radio("freq", []tele.MenuOption{
+//		{ID: "daily", Label: "Daily"},
+//		{ID: "weekly", Label: "Weekly"},
+//	}, getFreq, setFreq)
+//
+//	settings.Action("reset", "Reset", onReset)
+//	settings.Register(handler)
+//
+//	handler.Handle("/settings", settings.Send)
+type Menu struct {
+	// Text is the message text shown when the menu is opened.
+	Text string
+
+	// TextFunc overrides Text with a dynamic one.
+	TextFunc func(c Context) string
+
+	// Columns is the number of item buttons per row. Defaults to 1.
+	Columns int
+
+	// BackLabel and HomeLabel are the labels of the navigation buttons.
+	// Submenus inherit them from the root, which defaults to "« Back"
+	// and "« Home".
+	BackLabel, HomeLabel string
+
+	unique string
+	path   string
+	parent *Menu
+	items  []*MenuItem
+}
+
+// MenuItem is a button of the menu.
+type MenuItem struct {
+	// ID identifies the item among its siblings and is
+	// a part of the callback data, so keep it short.
+	ID string
+
+	// Label is the button text.
+	Label string
+
+	// LabelFunc overrides Label with a dynamic one.
+	LabelFunc func(c Context) string
+
+	submenu *Menu
+	action  HandlerFunc
+	toggle  *menuToggle
+	radio   *menuRadio
+}
+
+// MenuOption is an option of the radio selection.
+type MenuOption struct {
+	ID    string
+	Label string
+}
+
+type menuToggle struct {
+	get func(Context) bool
+	set func(Context, bool) error
+}
+
+type menuRadio struct {
+	options []MenuOption
+	get     func(Context) string
+	set     func(Context, string) error
+}
+
+func (r *menuRadio) has(id string) bool {
+	for _, opt := range r.options {
+		if opt.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Marks of the toggles and radio options.
+const (
+	MenuToggleOn  = "✅ "
+	MenuToggleOff = "☑️ "
+	MenuRadioOn   = "🔘 "
+	MenuRadioOff  = "⚪️ "
+)
+
+// ErrMenuNotFound is returned when the callback refers
+// to a menu path which doesn't exist anymore.
+var ErrMenuNotFound = errors.New("telebot: menu not found")
+
+// NewMenu returns the root menu with the callback unique used by all
+// of its buttons. Call Register to handle them.
+func NewMenu(unique, text string) *Menu {
+	return &Menu{unique: unique, Text: text}
+}
+
+// CallbackUnique implements CallbackEndpoint.
+func (m *Menu) CallbackUnique() string {
+	return "\f" + m.root().unique
+}
+
+// Register registers the callback handler of the menu tree.
+func (m *Menu) Register(h *Handler, mw ...MiddlewareFunc) {
+	h.Handle(m.root(), m.root().handle, mw...)
+}
+
+// Submenu adds the item opening a nested menu and returns it.
+func (m *Menu) Submenu(id, label, text string) *Menu {
+	sub := &Menu{
+		Text:   text,
+		path:   strings.TrimPrefix(m.path+"/"+id, "/"),
+		parent: m,
+	}
+	m.add(&MenuItem{ID: id, Label: label, submenu: sub})
+	return sub
+}
+
+// Action adds the item running the handler. The handler is
+// responsible for responding to the callback.
+func (m *Menu) Action(id, label string, h HandlerFunc) *MenuItem {
+	return m.add(&MenuItem{ID: id, Label: label, action: h})
+}
+
+// Toggle adds the item switching the boolean setting.
+// The label is prefixed with MenuToggleOn or MenuToggleOff.
+func (m *Menu) Toggle(id, label string, get func(Context) bool, set func(Context, bool) error) *MenuItem {
+	return m.add(&MenuItem{ID: id, Label: label, toggle: &menuToggle{get: get, set: set}})
+}
+
+// Radio adds the group of options, only one of them being selected.
+// Each option is a separate button prefixed with MenuRadioOn or MenuRadioOff.
+func (m *Menu) Radio(id string, options []MenuOption, get func(Context) string, set func(Context, string) error) *MenuItem {
+	return m.add(&MenuItem{ID: id, radio: &menuRadio{options: options, get: get, set: set}})
+}
+
+// Item returns the item of the menu by its ID, e.g.
+// to set the dynamic label of the submenu item.
+func (m *Menu) Item(id string) *MenuItem {
+	for _, item := range m.items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+func (m *Menu) add(item *MenuItem) *MenuItem {
+	m.items = append(m.items, item)
+	return item
+}
+
+func (m *Menu) root() *Menu {
+	for m.parent != nil {
+		m = m.parent
+	}
+	return m
+}
+
+// Send sends the menu to the current chat as a new message,
+// unlike Show, which edits the current one.
+func (m *Menu) Send(c Context) error {
+	return c.Send(m.text(c), m.Markup(c))
+}
+
+// Show edits the current message to show the menu.
+func (m *Menu) Show(c Context) error {
+	err := c.Edit(m.text(c), m.Markup(c))
+	if errors.Is(err, ErrMessageNotModified) || errors.Is(err, ErrSameMessageContent) {
+		return nil
+	}
+	return err
+}
+
+func (m *Menu) text(c Context) string {
+	if m.TextFunc != nil {
+		return m.TextFunc(c)
+	}
+	return m.Text
+}
+
+// Markup renders the menu keyboard with the navigation row.
+func (m *Menu) Markup(c Context) *ReplyMarkup {
+	root := m.root()
+
+	r := &ReplyMarkup{}
+	btn := func(label, path string, value ...string) Btn {
+		if path == "" && len(value) == 0 {
+			return r.Data(label, root.unique)
+		}
+		return r.Data(label, root.unique, append([]string{path}, value...)...)
+	}
+
+	var btns []Btn
+	for _, item := range m.items {
+		path := strings.TrimPrefix(m.path+"/"+item.ID, "/")
+
+		switch {
+		case item.radio != nil:
+			selected := item.radio.get(c)
+			for _, opt := range item.radio.options {
+				mark := MenuRadioOff
+				if opt.ID == selected {
+					mark = MenuRadioOn
+				}
+				btns = append(btns, btn(mark+opt.Label, path, opt.ID))
+			}
+		case item.toggle != nil:
+			mark := MenuToggleOff
+			if item.toggle.get(c) {
+				mark = MenuToggleOn
+			}
+			btns = append(btns, btn(mark+item.label(c), path))
+		default:
+			btns = append(btns, btn(item.label(c), path))
+		}
+	}
+
+	columns := m.Columns
+	if columns < 1 {
+		columns = 1
+	}
+	rows := r.Split(columns, btns)
+
+	if m.parent != nil {
+		back, home := root.BackLabel, root.HomeLabel
+		if back == "" {
+			back = "« Back"
+		}
+		if home == "" {
+			home = "« Home"
+		}
+
+		nav := Row{btn(back, m.parent.path)}
+		if m.parent.parent != nil {
+			nav = append(nav, btn(home, ""))
+		}
+		rows = append(rows, nav)
+	}
+
+	r.Inline(rows...)
+	return r
+}
+
+func (item *MenuItem) label(c Context) string {
+	if item.LabelFunc != nil {
+		return item.LabelFunc(c)
+	}
+	return item.Label
+}
+
+// find resolves the path to the menu and the item within it,
+// which is nil if the path points to the menu itself.
+func (m *Menu) find(path string) (*Menu, *MenuItem) {
+	if path == "" {
+		return m, nil
+	}
+
+	menu := m
+	ids := strings.Split(path, "/")
+	for i, id := range ids {
+		found := menu.Item(id)
+		if found == nil {
+			return nil, nil
+		}
+
+		if i == len(ids)-1 {
+			if found.submenu != nil {
+				return found.submenu, nil
+			}
+			return menu, found
+		}
+		if found.submenu == nil {
+			return nil, nil
+		}
+		menu = found.submenu
+	}
+
+	return nil, nil
+}
+
+func (m *Menu) handle(c Context) error {
+	var path, value string
+	if args := c.Args(); len(args) > 0 {
+		path = args[0]
+		if len(args) > 1 {
+			value = args[1]
+		}
+	}
+
+	// fail stops the button's spinner before returning the error.
+	fail := func(err error) error {
+		if rerr := c.Respond(); rerr != nil {
+			c.Bot().OnError(rerr, c)
+		}
+		return err
+	}
+
+	menu, item := m.find(path)
+	if menu == nil {
+		return fail(ErrMenuNotFound)
+	}
+
+	if item != nil {
+		switch {
+		case item.action != nil:
+			return item.action(c)
+		case item.toggle != nil:
+			if err := item.toggle.set(c, !item.toggle.get(c)); err != nil {
+				return fail(err)
+			}
+		case item.radio != nil:
+			if !item.radio.has(value) {
+				return fail(ErrMenuNotFound)
+			}
+			if err := item.radio.set(c, value); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	if err := menu.Show(c); err != nil {
+		return fail(err)
+	}
+	return c.Respond()
+}
@@ -0,0 +1,111 @@
+package telebot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMenu(t *testing.T) {
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		if method == "answerCallbackQuery" {
+			return ""
+		}
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	var errs []error
+	h := NewHandler(HandlerSettings{
+		Synchronous: true,
+		OnError:     func(err error, _ Context) { errs = append(errs, err) },
+	})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	var (
+		sound  bool
+		freq   = "daily"
+		reset  bool
+		setErr error
+	)
+
+	menu := NewMenu("settings", "Settings")
+	notify := menu.Submenu("notify", "Notifications", "Notification settings")
+	notify.Toggle("sound", "Sound",
+		func(Context) bool { return sound },
+		func(_ Context, on bool) error {
+			if setErr != nil {
+				return setErr
+			}
+			sound = on
+			return nil
+		})
+	notify.Radio("freq", []MenuOption{{ID: "daily", Label: "Daily"}, {ID: "weekly", Label: "Weekly"}},
+		func(Context) string { return freq },
+		func(_ Context, id string) error { freq = id; return nil })
+	deep := notify.Submenu("deep", "Deep", "Deep")
+	menu.Action("reset", "Reset", func(c Context) error { reset = true; return nil })
+	menu.Item("notify").LabelFunc = func(Context) string { return "🔔 Notifications" }
+	menu.Register(h)
+
+	markup := menu.Markup(nil)
+	require.Len(t, markup.InlineKeyboard, 2)
+	assert.Equal(t, "🔔 Notifications", markup.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "notify", markup.InlineKeyboard[0][0].Data)
+
+	markup = deep.Markup(nil)
+	require.Len(t, markup.InlineKeyboard, 1)
+	assert.Equal(t, "notify", markup.InlineKeyboard[0][0].Data)
+	assert.Equal(t, "", markup.InlineKeyboard[0][1].Data)
+
+	var requests []testRequest
+	click := func(data string) {
+		api.Reset()
+		b.ProcessUpdate(Update{Callback: &Callback{
+			ID:      "1",
+			Data:    "\fsettings|" + data,
+			Message: &Message{ID: 1, Chat: &Chat{ID: 1}},
+		}})
+		requests = api.Requests()
+	}
+
+	click("notify")
+	require.Len(t, requests, 2)
+	assert.Equal(t, "editMessageText", requests[0].Method)
+	assert.Equal(t, "Notification settings", requests[0].Params["text"])
+	assert.Contains(t, requests[0].Params["reply_markup"], MenuToggleOff+"Sound")
+	assert.Contains(t, requests[0].Params["reply_markup"], MenuRadioOn+"Daily")
+	assert.Contains(t, requests[0].Params["reply_markup"], "« Back")
+	assert.NotContains(t, requests[0].Params["reply_markup"], "« Home")
+
+	click("notify/sound")
+	assert.True(t, sound)
+	assert.Contains(t, requests[0].Params["reply_markup"], MenuToggleOn+"Sound")
+
+	click("notify/freq|weekly")
+	assert.Equal(t, "weekly", freq)
+	assert.Contains(t, requests[0].Params["reply_markup"], MenuRadioOn+"Weekly")
+
+	click("notify/freq|yearly")
+	assert.Equal(t, "weekly", freq)
+	assert.Equal(t, []error{ErrMenuNotFound}, errs)
+
+	click("reset")
+	assert.True(t, reset)
+	assert.Empty(t, requests)
+
+	click("missing")
+	assert.Len(t, errs, 2)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "answerCallbackQuery", requests[0].Method)
+
+	// The callback is answered even if the setting fails to save.
+	setErr = errors.New("db is down")
+	click("notify/sound")
+	assert.True(t, sound)
+	assert.Equal(t, setErr, errs[len(errs)-1])
+	require.Len(t, requests, 1)
+	assert.Equal(t, "answerCallbackQuery", requests[0].Method)
+}
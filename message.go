@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"strconv"
 	"time"
-	"unicode/utf16"
 )
 
 // Message object represents a message.
@@ -263,6 +262,9 @@ type Message struct {
 	// Message is a service message about a successful payment.
 	Payment *Payment `json:"successful_payment"`
 
+	// Message is a service message about a refunded payment.
+	RefundedPayment *RefundedPayment `json:"refunded_payment"`
+
 	// For a service message, a user was shared with the bot.
 	UserShared *RecipientShared `json:"users_shared,omitempty"`
 
@@ -474,14 +476,42 @@ func (m *Message) EntityText(e MessageEntity) string {
 		text = m.Caption
 	}
 
-	a := utf16.Encode([]rune(text))
-	off, end := e.Offset, e.Offset+e.Length
+	return utf16Substr(text, e.Offset, e.Length)
+}
 
-	if off < 0 || end > len(a) {
+// utf16Substr returns the substring identified by the offset and length
+// in UTF-16 code units. A boundary falling in the middle of a surrogate
+// pair is extended to the whole character, so it's never broken in half.
+func utf16Substr(s string, off, length int) string {
+	end := off + length
+	if off < 0 || length < 0 {
 		return ""
 	}
 
-	return string(utf16.Decode(a[off:end]))
+	start, stop := -1, -1
+	n := 0
+	for i, r := range s {
+		size := 1
+		if r >= 0x10000 {
+			size = 2
+		}
+		if start < 0 && n+size > off {
+			start = i
+		}
+		if n >= end {
+			stop = i
+			break
+		}
+		n += size
+	}
+
+	if start < 0 || (stop < 0 && n < end) {
+		return ""
+	}
+	if stop < 0 {
+		stop = len(s)
+	}
+	return s[start:stop]
 }
 
 // Media returns the message's media if it contains either photo,
@@ -697,28 +727,28 @@ type ReplyParams struct {
 
 	// (Optional) If the message to be replied to is from a different chat,
 	// unique identifier for the chat or username of the channel.
-	ChatID int64 `json:"chat_id"`
+	ChatID int64 `json:"chat_id,omitempty"`
 
 	// Optional. Pass True if the message should be sent even if the specified message
 	// to be replied to is not found; can be used only for replies in the
 	// same chat and forum topic.
-	AllowWithoutReply bool `json:"allow_sending_without_reply"`
+	AllowWithoutReply bool `json:"allow_sending_without_reply,omitempty"`
 
 	// (Optional) Quoted part of the message to be replied to; 0-1024 characters after
 	// entities parsing. The quote must be an exact substring of the message to be replied to,
 	// including bold, italic, underline, strikethrough, spoiler, and custom_emoji entities.
 	// The message will fail to send if the quote isn't found in the original message.
-	Quote string `json:"quote"`
+	Quote string `json:"quote,omitempty"`
 
 	// (Optional) Mode for parsing entities in the quote.
-	QuoteParseMode ParseMode `json:"quote_parse_mode"`
+	QuoteParseMode ParseMode `json:"quote_parse_mode,omitempty"`
 
 	// (Optional) A JSON-serialized list of special entities that appear in the quote.
 	// It can be specified instead of quote_parse_mode.
-	QuoteEntities []MessageEntity `json:"quote_entities"`
+	QuoteEntities []MessageEntity `json:"quote_entities,omitempty"`
 
 	// (Optional) Position of the quote in the original message in UTF-16 code units.
-	QuotePosition int `json:"quote_position"`
+	QuotePosition int `json:"quote_position,omitempty"`
 }
 
 // React changes the chosen reactions on a message. Service messages can't be
@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+// DefaultMemberTTL is the default time a chat member is cached for.
+const DefaultMemberTTL = 5 * time.Minute
+
+// MemberCache caches chat members fetched with Bot.ChatMemberOf.
+// Entries are refreshed by chat member updates passed through the
+// Track middleware and expire after TTL otherwise.
+type MemberCache struct {
+	ttl time.Duration
+
+	mu        sync.RWMutex
+	members   map[memberKey]cachedMember
+	lastSweep time.Time
+}
+
+type memberKey struct {
+	chatID int64
+	userID int64
+}
+
+type cachedMember struct {
+	member  tele.ChatMember
+	expires time.Time
+}
+
+// NewMemberCache returns a new MemberCache. Non-positive ttl
+// means DefaultMemberTTL.
+func NewMemberCache(ttl time.Duration) *MemberCache {
+	if ttl <= 0 {
+		ttl = DefaultMemberTTL
+	}
+	return &MemberCache{
+		ttl:     ttl,
+		members: make(map[memberKey]cachedMember),
+	}
+}
+
+// Member returns the cached chat member, fetching it
+// from Telegram when it's missing or expired.
+func (mc *MemberCache) Member(b *tele.Bot, chat *tele.Chat, user *tele.User) (*tele.ChatMember, error) {
+	key := memberKey{chatID: chat.ID, userID: user.ID}
+
+	mc.mu.RLock()
+	cm, ok := mc.members[key]
+	mc.mu.RUnlock()
+
+	if ok && now().Before(cm.expires) {
+		m := cm.member
+		return &m, nil
+	}
+
+	m, err := b.ChatMemberOf(chat, user)
+	if err != nil {
+		return nil, err
+	}
+
+	mc.Set(chat.ID, m)
+	return m, nil
+}
+
+// Set puts the chat member into the cache.
+func (mc *MemberCache) Set(chatID int64, m *tele.ChatMember) {
+	if m == nil || m.User == nil {
+		return
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	n := now()
+	if n.Sub(mc.lastSweep) >= mc.ttl {
+		for key, cm := range mc.members {
+			if n.After(cm.expires) {
+				delete(mc.members, key)
+			}
+		}
+		mc.lastSweep = n
+	}
+
+	mc.members[memberKey{chatID: chatID, userID: m.User.ID}] = cachedMember{
+		member:  *m,
+		expires: n.Add(mc.ttl),
+	}
+}
+
+// Invalidate removes the chat member from the cache.
+func (mc *MemberCache) Invalidate(chatID, userID int64) {
+	mc.mu.Lock()
+	delete(mc.members, memberKey{chatID: chatID, userID: userID})
+	mc.mu.Unlock()
+}
+
+// Track returns a middleware that keeps the cache up to date with
+// the OnChatMember and OnMyChatMember updates. Keep in mind, it only
+// runs for the updates which have their handlers registered.
+//
+// Usage:
+//
+//	cache := middleware.NewMemberCache(0)
+//	b.Use(cache.Track())
+//	b.Handle(tele.OnChatMember, func(c tele.Context) error { return nil })
+func (mc *MemberCache) Track() tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			if u := c.ChatMember(); u != nil && u.Chat != nil {
+				if u.NewChatMember != nil {
+					mc.Set(u.Chat.ID, u.NewChatMember)
+				} else if u.OldChatMember != nil && u.OldChatMember.User != nil {
+					mc.Invalidate(u.Chat.ID, u.OldChatMember.User.ID)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// PermissionConfig defines config for Permission middleware.
+type PermissionConfig struct {
+	// Cache is used to look up the sender's membership.
+	// If nil, the new cache with the default TTL is used.
+	Cache *MemberCache
+
+	// Roles is a list of statuses allowed to pass.
+	// Defaults to creator and administrator.
+	Roles []tele.MemberStatus
+
+	// Rights defines an optional check of the sender's rights,
+	// e.g. CanRestrictMembers. The chat creator always passes it.
+	Rights func(tele.Rights) bool
+
+	// AllowAnonymous lets the anonymous administrators pass, meaning
+	// the messages sent on behalf of the chat itself. Their rights
+	// can't be checked, so use it for non-destructive actions only.
+	AllowAnonymous bool
+
+	// Out defines a function that will be called if the sender
+	// doesn't have the permissions. Defaults to skipping the update.
+	Out tele.HandlerFunc
+}
+
+// Permission returns a middleware that passes the update only if its
+// sender has one of the configured statuses and rights in the chat.
+// Updates from private chats and without a sender are never passed.
+//
+// Usage:
+//
+//	b.Handle("/ban", onBan, middleware.Permission(middleware.PermissionConfig{
+//		Cache: cache,
+//		Rights: func(r tele.Rights) bool {
+//			return r.CanRestrictMembers
+//		},
+//	}))
+func Permission(v PermissionConfig) tele.MiddlewareFunc {
+	if v.Cache == nil {
+		v.Cache = NewMemberCache(0)
+	}
+	if len(v.Roles) == 0 {
+		v.Roles = []tele.MemberStatus{tele.Creator, tele.Administrator}
+	}
+	if v.Out == nil {
+		v.Out = func(c tele.Context) error { return nil }
+	}
+
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			chat := c.Chat()
+			if chat == nil || chat.Type == tele.ChatPrivate {
+				return v.Out(c)
+			}
+
+			if msg := c.Message(); msg != nil && isAnonymousAdmin(msg) {
+				if v.AllowAnonymous {
+					return next(c)
+				}
+				return v.Out(c)
+			}
+
+			user := c.Sender()
+			if user == nil {
+				return v.Out(c)
+			}
+
+			m, err := v.Cache.Member(c.Bot(), chat, user)
+			if err != nil {
+				return err
+			}
+
+			if !hasRole(m.Role, v.Roles) {
+				return v.Out(c)
+			}
+			if v.Rights != nil && m.Role != tele.Creator && !v.Rights(m.Rights) {
+				return v.Out(c)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// AdminOnly returns a middleware that passes the update only
+// if its sender is an administrator or the creator of the chat.
+func AdminOnly(cache *MemberCache) tele.MiddlewareFunc {
+	return Permission(PermissionConfig{Cache: cache})
+}
+
+// isAnonymousAdmin reports whether the message is sent by an
+// anonymous administrator on behalf of the chat itself.
+func isAnonymousAdmin(msg *tele.Message) bool {
+	return msg.SenderChat != nil && msg.Chat != nil && msg.SenderChat.ID == msg.Chat.ID
+}
+
+func hasRole(role tele.MemberStatus, roles []tele.MemberStatus) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+func TestPermission(t *testing.T) {
+	group := &tele.Chat{ID: -100, Type: tele.ChatSuperGroup}
+	admin := &tele.User{ID: 1}
+	moder := &tele.User{ID: 2}
+
+	cache := NewMemberCache(0)
+	cache.Set(group.ID, &tele.ChatMember{User: admin, Role: tele.Creator})
+	cache.Set(group.ID, &tele.ChatMember{User: moder, Role: tele.Administrator})
+
+	var handled, rejected int
+	h := Permission(PermissionConfig{
+		Cache: cache,
+		Rights: func(r tele.Rights) bool {
+			return r.CanRestrictMembers
+		},
+		Out: func(c tele.Context) error {
+			rejected++
+			return nil
+		},
+	})(func(c tele.Context) error {
+		handled++
+		return nil
+	})
+
+	msg := func(sender *tele.User, senderChat *tele.Chat) tele.Context {
+		return b.NewContext(tele.Update{Message: &tele.Message{
+			Sender:     sender,
+			SenderChat: senderChat,
+			Chat:       group,
+		}})
+	}
+
+	assert.NoError(t, h(msg(admin, nil)))
+	assert.Equal(t, 1, handled)
+
+	assert.NoError(t, h(msg(moder, nil)))
+	assert.Equal(t, 1, rejected)
+
+	// Anonymous admins are rejected by default.
+	assert.NoError(t, h(msg(&tele.User{ID: 1087968824}, group)))
+	assert.Equal(t, 2, rejected)
+
+	// Promotion comes with the chat member update.
+	track := cache.Track()(func(c tele.Context) error { return nil })
+	track(b.NewContext(tele.Update{ChatMember: &tele.ChatMemberUpdate{
+		Chat: group,
+		NewChatMember: &tele.ChatMember{
+			User:   moder,
+			Role:   tele.Administrator,
+			Rights: tele.Rights{CanRestrictMembers: true},
+		},
+	}}))
+
+	assert.NoError(t, h(msg(moder, nil)))
+	assert.Equal(t, 2, handled)
+
+	private := b.NewContext(tele.Update{Message: &tele.Message{
+		Sender: admin,
+		Chat:   &tele.Chat{ID: admin.ID, Type: tele.ChatPrivate},
+	}})
+	assert.NoError(t, h(private))
+	assert.Equal(t, 3, rejected)
+}
+
+func TestPermissionAnonymous(t *testing.T) {
+	group := &tele.Chat{ID: -100, Type: tele.ChatSuperGroup}
+
+	var handled int
+	h := Permission(PermissionConfig{AllowAnonymous: true})(func(c tele.Context) error {
+		handled++
+		return nil
+	})
+
+	assert.NoError(t, h(b.NewContext(tele.Update{Message: &tele.Message{
+		Sender:     &tele.User{ID: 1087968824},
+		SenderChat: group,
+		Chat:       group,
+	}})))
+	assert.Equal(t, 1, handled)
+}
@@ -1,7 +1,10 @@
 package middleware
 
 import (
-	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
 
 	tele "github.com/vadimpk/telebot"
 )
@@ -34,26 +37,107 @@ func IgnoreVia() tele.MiddlewareFunc {
 
 type RecoverFunc = func(error, tele.Context)
 
+// PanicError is an error a recovered panic is converted into.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine at the panic moment.
+	Stack []byte
+
+	// UpdateID is the ID of the update being handled.
+	UpdateID int
+
+	// Endpoint is the endpoint of the handler panicked.
+	Endpoint string
+}
+
+// Error implements error interface.
+func (err *PanicError) Error() string {
+	msg := fmt.Sprintf("telebot: panic: %v", err.Value)
+	if err.Endpoint != "" {
+		msg += fmt.Sprintf(" (endpoint %q, update %d)", strings.TrimLeft(err.Endpoint, "\a\f"), err.UpdateID)
+	}
+	return msg
+}
+
+// newPanicError converts the recovered value into a *PanicError
+// with the stack trace of the current goroutine.
+func newPanicError(r interface{}, c tele.Context) *PanicError {
+	perr := &PanicError{
+		Value: r,
+		Stack: debug.Stack(),
+	}
+	if c != nil {
+		perr.UpdateID = c.Update().ID
+		perr.Endpoint = c.Endpoint()
+	}
+	return perr
+}
+
+// Unwrap returns the panic value if it's an error.
+func (err *PanicError) Unwrap() error {
+	if e, ok := err.Value.(error); ok {
+		return e
+	}
+	return nil
+}
+
+// RecoverConfig defines config for RecoverWith middleware.
+type RecoverConfig struct {
+	// OnError defines a function that will be called with the *PanicError.
+	// Defaults to the bot's OnError.
+	OnError RecoverFunc
+
+	// Notify is an optional recipient, e.g. the admin chat, that will
+	// receive a short summary of every recovered panic.
+	Notify tele.Recipient
+}
+
 // Recover returns a middleware that recovers a panic happened in
-// the handler.
+// the handler. Every panic value is passed to the onError function
+// as a *PanicError, which carries the stack trace.
 func Recover(onError ...RecoverFunc) tele.MiddlewareFunc {
-	return func(next tele.HandlerFunc) tele.HandlerFunc {
-		return func(c tele.Context) error {
-			var f RecoverFunc
-			if len(onError) > 0 {
-				f = onError[0]
+	var v RecoverConfig
+	if len(onError) > 0 {
+		v.OnError = onError[0]
+	}
+	return RecoverWith(v)
+}
+
+// RecoverWith returns a middleware that recovers a panic happened in
+// the handler, converting it into a *PanicError and optionally
+// notifying the configured recipient.
+func RecoverWith(v RecoverConfig) tele.MiddlewareFunc {
+	f := v.OnError
+	if f == nil {
+		f = func(err error, c tele.Context) {
+			if c != nil {
+				c.Bot().OnError(err, c)
 			} else {
-				f = func(err error, c tele.Context) {
-					c.Bot().OnError(err, c)
-				}
+				log.Println(err)
 			}
+		}
+	}
 
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
 			defer func() {
-				if r := recover(); r != nil {
-					if err, ok := r.(error); ok {
+				r := recover()
+				if r == nil {
+					return
+				}
+
+				perr, ok := r.(*PanicError)
+				if !ok {
+					perr = newPanicError(r, c)
+				}
+
+				f(perr, c)
+
+				if v.Notify != nil && c != nil {
+					if _, err := c.Bot().Send(v.Notify, perr.Error()); err != nil {
 						f(err, c)
-					} else if s, ok := r.(string); ok {
-						f(errors.New(s), c)
 					}
 				}
 			}()
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,7 +13,7 @@ import (
 var b, _ = tele.NewBot(tele.Settings{Offline: true})
 
 func TestRecover(t *testing.T) {
-	onError := func(err error) {
+	onError := func(err error, c tele.Context) {
 		require.Error(t, err, "recover test")
 	}
 
@@ -28,3 +29,30 @@ func TestRecover(t *testing.T) {
 		Recover(onError)(h)(nil)
 	})
 }
+
+func TestRecoverValues(t *testing.T) {
+	var got error
+	onError := func(err error, c tele.Context) {
+		got = err
+	}
+
+	for _, v := range []interface{}{42, struct{}{}, "text", errors.New("error")} {
+		got = nil
+		h := func(c tele.Context) error {
+			panic(v)
+		}
+
+		c := b.NewContext(tele.Update{ID: 7})
+		assert.NotPanics(t, func() {
+			Recover(onError)(h)(c)
+		})
+
+		var perr *PanicError
+		require.True(t, errors.As(got, &perr))
+		assert.Equal(t, v, perr.Value)
+		assert.Equal(t, 7, perr.UpdateID)
+		assert.NotEmpty(t, perr.Stack)
+	}
+
+	assert.True(t, errors.Is(got, got.(*PanicError).Value.(error)))
+}
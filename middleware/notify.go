@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"time"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+// NotifyConfig defines config for NotifyWith middleware.
+type NotifyConfig struct {
+	// Action is a chat action to be sent, e.g. tele.Typing.
+	Action tele.ChatAction
+
+	// Delay defines how long to wait before sending the first action,
+	// so the fast handlers don't trigger it at all. Defaults to 500ms.
+	Delay time.Duration
+
+	// Interval defines how often the action is refreshed while the
+	// handler is running. Telegram shows the action for 5 seconds,
+	// so it defaults to 4 seconds.
+	Interval time.Duration
+}
+
+// Notify returns a middleware that keeps sending the chat action
+// while the handler is running. See NotifyWith.
+func Notify(action tele.ChatAction) tele.MiddlewareFunc {
+	return NotifyWith(NotifyConfig{Action: action})
+}
+
+// NotifyWith returns a middleware that starts sending the chat action
+// to the current recipient after a short delay and refreshes it while
+// the handler is running. No new action is sent once the handler returns,
+// but the handler doesn't wait for the one in flight, which may still
+// arrive after the reply and show the action for up to 5 seconds.
+// For the forum topic messages the action is sent to the corresponding
+// thread.
+//
+// Usage:
+//
+//	b.Handle("/report", onReport, middleware.Notify(tele.UploadingDocument))
+func NotifyWith(v NotifyConfig) tele.MiddlewareFunc {
+	if v.Delay <= 0 {
+		v.Delay = 500 * time.Millisecond
+	}
+	if v.Interval <= 0 {
+		v.Interval = 4 * time.Second
+	}
+
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			to := c.Recipient()
+			if to == nil {
+				return next(c)
+			}
+
+			var threadID []int
+			if msg := c.Message(); msg != nil && msg.TopicMessage {
+				threadID = append(threadID, msg.ThreadID)
+			}
+
+			done := make(chan struct{})
+			defer close(done)
+
+			go func() {
+				timer := time.NewTimer(v.Delay)
+				defer timer.Stop()
+
+				select {
+				case <-done:
+					return
+				case <-timer.C:
+				}
+
+				ticker := time.NewTicker(v.Interval)
+				defer ticker.Stop()
+
+				for {
+					// The handler may have returned while both
+					// of the channels below were ready.
+					select {
+					case <-done:
+						return
+					default:
+					}
+
+					if err := c.Bot().Notify(to, v.Action, threadID...); err != nil {
+						c.Bot().OnError(err, c)
+						return
+					}
+
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+func TestNotify(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		actions []string
+	)
+	inflight, release := make(chan struct{}), make(chan struct{})
+
+	// The third action is held until the test is over.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendChatAction") {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			actions = append(actions, string(body))
+			n := len(actions)
+			mu.Unlock()
+
+			if n == 3 {
+				close(inflight)
+				<-release
+			}
+		}
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	b, err := tele.NewBot(tele.Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	h := NotifyWith(NotifyConfig{
+		Action:   tele.Typing,
+		Delay:    time.Millisecond,
+		Interval: time.Millisecond,
+	})(func(c tele.Context) error {
+		select {
+		case <-inflight:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("actions were not refreshed")
+		}
+	})
+
+	c := b.NewContext(tele.Update{Message: &tele.Message{
+		Chat:         &tele.Chat{ID: 1},
+		ThreadID:     5,
+		TopicMessage: true,
+	}})
+
+	// The handler doesn't wait for the action in flight.
+	result := make(chan error, 1)
+	go func() { result <- h(c) }()
+	select {
+	case err := <-result:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler waits for the action in flight")
+	}
+
+	mu.Lock()
+	sent := actions
+	actions = nil
+	mu.Unlock()
+
+	require.Len(t, sent, 3)
+	assert.Contains(t, sent[0], `"action":"typing"`)
+	assert.Contains(t, sent[0], `"message_thread_id":"5"`)
+
+	fast := Notify(tele.Typing)(func(c tele.Context) error { return nil })
+	require.NoError(t, fast(c))
+
+	mu.Lock()
+	assert.Empty(t, actions)
+	mu.Unlock()
+}
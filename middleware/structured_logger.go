@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+// LogLevel is a severity of the log entry.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String implements fmt.Stringer.
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// LogField is a single key/value pair of the log entry.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// LogSink receives the entries produced by StructuredLogger.
+// Implement it to bridge the logger with your logging library.
+type LogSink interface {
+	Log(level LogLevel, msg string, fields []LogField)
+}
+
+// Redacted replaces the values of the redacted fields.
+const Redacted = "[redacted]"
+
+// Log field keys produced by StructuredLogger.
+const (
+	FieldUpdateID = "update_id"
+	FieldType     = "type"
+	FieldChat     = "chat"
+	FieldUser     = "user"
+	FieldUsername = "username"
+	FieldEndpoint = "endpoint"
+	FieldText     = "text"
+	FieldPhone    = "phone"
+	FieldDuration = "duration"
+	FieldError    = "error"
+)
+
+// LoggerConfig defines config for StructuredLogger middleware.
+type LoggerConfig struct {
+	// Sink receives the log entries.
+	// Defaults to the sink writing to log.Default().
+	Sink LogSink
+
+	// Level is the minimal level of entries to be logged. Updates are
+	// logged with LevelInfo, the ones resulted in an error with LevelError.
+	Level LogLevel
+
+	// Sample logs only every N-th entry below LevelError.
+	// Zero or one means every entry is logged.
+	Sample int
+
+	// Redact is a list of field keys whose values are replaced with
+	// Redacted. Defaults to FieldText, FieldPhone and FieldUsername.
+	// Redacting FieldText also redacts the endpoints of the handlers
+	// matched by the whole message text.
+	Redact []string
+}
+
+// StructuredLogger returns a middleware that logs each handled update
+// as a compact list of key/value fields along with the handler
+// duration and its error.
+//
+// Usage:
+//
+//	b.Use(middleware.StructuredLogger(middleware.LoggerConfig{
+//		Level:  middleware.LevelInfo,
+//		Sample: 10,
+//		Redact: []string{middleware.FieldText},
+//	}))
+func StructuredLogger(v LoggerConfig) tele.MiddlewareFunc {
+	if v.Sink == nil {
+		v.Sink = NewLogSink(log.Default())
+	}
+	if v.Redact == nil {
+		v.Redact = []string{FieldText, FieldPhone, FieldUsername}
+	}
+
+	redact := make(map[string]bool, len(v.Redact))
+	for _, key := range v.Redact {
+		redact[key] = true
+	}
+
+	var counter uint64
+
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			start := now()
+			err := next(c)
+
+			level := LevelInfo
+			if err != nil {
+				level = LevelError
+			}
+			if level < v.Level {
+				return err
+			}
+			if level < LevelError && v.Sample > 1 {
+				if atomic.AddUint64(&counter, 1)%uint64(v.Sample) != 1 {
+					return err
+				}
+			}
+
+			fields := updateFields(c)
+			fields = append(fields, LogField{FieldDuration, now().Sub(start)})
+			if err != nil {
+				fields = append(fields, LogField{FieldError, err.Error()})
+			}
+
+			for i, f := range fields {
+				if redact[f.Key] || f.Key == FieldEndpoint && redact[FieldText] && textEndpoint(c.Endpoint()) {
+					fields[i].Value = Redacted
+				}
+			}
+
+			v.Sink.Log(level, "update", fields)
+			return err
+		}
+	}
+}
+
+func updateFields(c tele.Context) []LogField {
+	u := c.Update()
+
+	fields := []LogField{
+		{FieldUpdateID, u.ID},
+		{FieldType, UpdateType(u)},
+	}
+	if chat := c.Chat(); chat != nil {
+		fields = append(fields, LogField{FieldChat, chat.ID})
+	}
+	if user := c.Sender(); user != nil {
+		fields = append(fields, LogField{FieldUser, user.ID})
+		if user.Username != "" {
+			fields = append(fields, LogField{FieldUsername, user.Username})
+		}
+	}
+	if end := c.Endpoint(); end != "" {
+		fields = append(fields, LogField{FieldEndpoint, strings.TrimLeft(end, "\a\f")})
+	}
+	if text := c.Text(); text != "" {
+		fields = append(fields, LogField{FieldText, text})
+	}
+	if msg := c.Message(); msg != nil && msg.Contact != nil {
+		fields = append(fields, LogField{FieldPhone, msg.Contact.PhoneNumber})
+	}
+
+	return fields
+}
+
+// textEndpoint reports whether the handler was matched by the whole
+// message text, so the endpoint is as sensitive as the text itself.
+func textEndpoint(end string) bool {
+	return end != "" && end[0] != '\a' && end[0] != '\f' && end[0] != '/'
+}
+
+// UpdateType returns the Bot API name of the update kind,
+// e.g. "message" or "callback_query".
+func UpdateType(u tele.Update) string {
+	switch {
+	case u.Message != nil:
+		return "message"
+	case u.EditedMessage != nil:
+		return "edited_message"
+	case u.ChannelPost != nil:
+		return "channel_post"
+	case u.EditedChannelPost != nil:
+		return "edited_channel_post"
+	case u.MessageReaction != nil:
+		return "message_reaction"
+	case u.MessageReactionCount != nil:
+		return "message_reaction_count"
+	case u.Callback != nil:
+		return "callback_query"
+	case u.Query != nil:
+		return "inline_query"
+	case u.InlineResult != nil:
+		return "chosen_inline_result"
+	case u.ShippingQuery != nil:
+		return "shipping_query"
+	case u.PreCheckoutQuery != nil:
+		return "pre_checkout_query"
+	case u.Poll != nil:
+		return "poll"
+	case u.PollAnswer != nil:
+		return "poll_answer"
+	case u.MyChatMember != nil:
+		return "my_chat_member"
+	case u.ChatMember != nil:
+		return "chat_member"
+	case u.ChatJoinRequest != nil:
+		return "chat_join_request"
+	case u.Boost != nil:
+		return "chat_boost"
+	case u.BoostRemoved != nil:
+		return "removed_chat_boost"
+	default:
+		return "unknown"
+	}
+}
+
+// NewLogSink returns a LogSink writing the entries to the logger
+// in the logfmt-like form:
+//
+//	level=info msg=update update_id=1 type=message chat=42 duration=1.2ms
+func NewLogSink(l *log.Logger) LogSink {
+	return &stdSink{l: l}
+}
+
+type stdSink struct {
+	l *log.Logger
+}
+
+func (s *stdSink) Log(level LogLevel, msg string, fields []LogField) {
+	var sb strings.Builder
+	sb.WriteString("level=")
+	sb.WriteString(level.String())
+	sb.WriteString(" msg=")
+	sb.WriteString(quoteValue(msg))
+
+	for _, f := range fields {
+		sb.WriteByte(' ')
+		sb.WriteString(f.Key)
+		sb.WriteByte('=')
+		sb.WriteString(quoteValue(fmt.Sprint(f.Value)))
+	}
+
+	s.l.Println(sb.String())
+}
+
+func quoteValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\n\t") {
+		return strconv.Quote(s)
+	}
+	return s
+}
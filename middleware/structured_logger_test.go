@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+type testSink struct {
+	levels  []LogLevel
+	entries []map[string]interface{}
+}
+
+func (s *testSink) Log(level LogLevel, msg string, fields []LogField) {
+	entry := make(map[string]interface{})
+	for _, f := range fields {
+		entry[f.Key] = f.Value
+	}
+	s.levels = append(s.levels, level)
+	s.entries = append(s.entries, entry)
+}
+
+func TestStructuredLogger(t *testing.T) {
+	sink := &testSink{}
+	h := StructuredLogger(LoggerConfig{Sink: sink})(func(c tele.Context) error {
+		if c.Text() == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	c := b.NewContext(tele.Update{
+		ID: 7,
+		Message: &tele.Message{
+			Text:    "+1 555 0100",
+			Sender:  &tele.User{ID: 1, Username: "jon"},
+			Chat:    &tele.Chat{ID: 2},
+			Contact: &tele.Contact{PhoneNumber: "+1 555 0100"},
+		},
+	})
+	require.NoError(t, h(c))
+	require.Len(t, sink.entries, 1)
+
+	entry := sink.entries[0]
+	assert.Equal(t, LevelInfo, sink.levels[0])
+	assert.Equal(t, 7, entry[FieldUpdateID])
+	assert.Equal(t, "message", entry[FieldType])
+	assert.Equal(t, int64(2), entry[FieldChat])
+	assert.Equal(t, int64(1), entry[FieldUser])
+	assert.Equal(t, Redacted, entry[FieldUsername])
+	assert.Equal(t, Redacted, entry[FieldText])
+	assert.Equal(t, Redacted, entry[FieldPhone])
+	assert.Contains(t, entry, FieldDuration)
+
+	c = b.NewContext(tele.Update{Message: &tele.Message{Text: "fail"}})
+	require.Error(t, h(c))
+	assert.Equal(t, LevelError, sink.levels[1])
+	assert.Equal(t, "failed", sink.entries[1][FieldError])
+}
+
+func TestStructuredLoggerFiltering(t *testing.T) {
+	sink := &testSink{}
+	h := StructuredLogger(LoggerConfig{Sink: sink, Sample: 3})(func(c tele.Context) error {
+		return nil
+	})
+
+	c := b.NewContext(tele.Update{Message: &tele.Message{}})
+	for i := 0; i < 6; i++ {
+		h(c)
+	}
+	assert.Len(t, sink.entries, 2)
+
+	sink = &testSink{}
+	h = StructuredLogger(LoggerConfig{Sink: sink, Level: LevelError})(func(c tele.Context) error {
+		return nil
+	})
+	h(c)
+	assert.Empty(t, sink.entries)
+}
+
+func TestStructuredLoggerTextEndpoint(t *testing.T) {
+	h := tele.NewHandler(tele.HandlerSettings{Synchronous: true})
+	b, err := tele.NewBot(tele.Settings{Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	sink := &testSink{}
+	logger := StructuredLogger(LoggerConfig{Sink: sink})
+	h.Handle("my password", func(c tele.Context) error { return nil }, logger)
+	h.Handle("/start", func(c tele.Context) error { return nil }, logger)
+
+	b.ProcessUpdate(tele.Update{Message: &tele.Message{Text: "my password", Chat: &tele.Chat{ID: 1}}})
+	b.ProcessUpdate(tele.Update{Message: &tele.Message{Text: "/start", Chat: &tele.Chat{ID: 1}}})
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, Redacted, sink.entries[0][FieldEndpoint])
+	assert.Equal(t, "/start", sink.entries[1][FieldEndpoint])
+
+	sink = &testSink{}
+	h.Handle("my password", func(c tele.Context) error { return nil },
+		StructuredLogger(LoggerConfig{Sink: sink, Redact: []string{FieldPhone}}))
+
+	b.ProcessUpdate(tele.Update{Message: &tele.Message{Text: "my password", Chat: &tele.Chat{ID: 1}}})
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, "my password", sink.entries[0][FieldEndpoint])
+}
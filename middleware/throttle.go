@@ -0,0 +1,280 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// ThrottleConfig defines config for Throttle middleware.
+type ThrottleConfig struct {
+	// UserRate is a number of updates per second allowed for
+	// a single user. Zero disables the per-user limit.
+	UserRate float64
+
+	// UserBurst is a maximum number of updates a user can send
+	// at once. Defaults to 1.
+	UserBurst int
+
+	// ChatRate is a number of updates per second allowed for
+	// a single chat. Zero disables the per-chat limit.
+	ChatRate float64
+
+	// ChatBurst is a maximum number of updates a chat can produce
+	// at once. Defaults to 1.
+	ChatBurst int
+
+	// Strikes is a number of updates in a row rejected by the user
+	// rate, after which the user gets ignored for the IgnoreFor
+	// duration. The chat rate never counts. Zero disables the escalation.
+	Strikes int
+
+	// IgnoreFor defines for how long the user is ignored
+	// after the escalation.
+	IgnoreFor time.Duration
+
+	// OnThrottle defines a function that will be called for the
+	// rejected update, e.g. to answer the callback with a warning.
+	// It isn't called for the updates of ignored users.
+	OnThrottle tele.HandlerFunc
+}
+
+// Throttle returns a middleware that limits the rate of updates
+// per user and per chat using token buckets. Updates exceeding the
+// limits are skipped and passed to the OnThrottle function.
+//
+// Usage:
+//
+//	b.Use(middleware.Throttle(middleware.ThrottleConfig{
+//		UserRate:  1,
+//		UserBurst: 3,
+//		Strikes:   10,
+//		IgnoreFor: time.Minute,
+//		OnThrottle: func(c tele.Context) error {
+//			if c.Callback() != nil {
+//				return c.RespondText("Slow down!")
+//			}
+//			return nil
+//		},
+//	}))
+func Throttle(v ThrottleConfig) tele.MiddlewareFunc {
+	t := newThrottler(v)
+
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			var userID, chatID int64
+			if user := c.Sender(); user != nil {
+				userID = user.ID
+			}
+			if chat := c.Chat(); chat != nil {
+				chatID = chat.ID
+			}
+
+			switch t.allow(userID, chatID) {
+			case throttleIgnored:
+				return nil
+			case throttleRejected:
+				if v.OnThrottle != nil {
+					return v.OnThrottle(c)
+				}
+				return nil
+			}
+
+			return next(c)
+		}
+	}
+}
+
+type throttleResult int
+
+const (
+	throttleAllowed throttleResult = iota
+	throttleRejected
+	throttleIgnored
+)
+
+type throttler struct {
+	config ThrottleConfig
+
+	mu    sync.Mutex
+	users *buckets
+	chats *buckets
+	peers map[int64]*peer
+
+	// peerTTL is the time after the last strike the peer is
+	// forgotten in, once the buckets are refilled and the
+	// ignoring period is over.
+	peerTTL   time.Duration
+	lastSweep time.Time
+}
+
+type peer struct {
+	strikes      int
+	lastStrike   time.Time
+	ignoredUntil time.Time
+}
+
+func newThrottler(v ThrottleConfig) *throttler {
+	t := &throttler{
+		config:  v,
+		users:   newBuckets(v.UserRate, v.UserBurst),
+		chats:   newBuckets(v.ChatRate, v.ChatBurst),
+		peers:   make(map[int64]*peer),
+		peerTTL: v.IgnoreFor,
+	}
+	for _, d := range []time.Duration{t.users.interval(), t.chats.interval()} {
+		if d > t.peerTTL {
+			t.peerTTL = d
+		}
+	}
+	return t
+}
+
+func (t *throttler) allow(userID, chatID int64) throttleResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := now()
+	t.sweep(n)
+
+	p := t.peers[userID]
+	if p != nil && n.Before(p.ignoredUntil) {
+		return throttleIgnored
+	}
+
+	// Both buckets must be checked before taking the tokens,
+	// so the rejected update isn't charged to any of them.
+	userReady := userID == 0 || t.users.ready(userID, n)
+	chatReady := chatID == 0 || t.chats.ready(chatID, n)
+
+	if userReady && chatReady {
+		if userID != 0 {
+			t.users.take(userID)
+		}
+		if chatID != 0 {
+			t.chats.take(chatID)
+		}
+		delete(t.peers, userID)
+		return throttleAllowed
+	}
+
+	// The user isn't punished for the busy chat,
+	// only their own bucket running out is a strike.
+	if userReady || t.config.Strikes <= 0 {
+		return throttleRejected
+	}
+
+	if p == nil {
+		p = &peer{}
+		t.peers[userID] = p
+	}
+
+	p.strikes++
+	p.lastStrike = n
+	if p.strikes >= t.config.Strikes {
+		p.strikes = 0
+		p.ignoredUntil = n.Add(t.config.IgnoreFor)
+		return throttleIgnored
+	}
+
+	return throttleRejected
+}
+
+// sweep forgets the peers with no strikes for the peer TTL, so the
+// map doesn't grow with every user ever throttled.
+func (t *throttler) sweep(n time.Time) {
+	if n.Sub(t.lastSweep) < t.peerTTL {
+		return
+	}
+
+	for id, p := range t.peers {
+		if n.Sub(p.lastStrike) >= t.peerTTL && !n.Before(p.ignoredUntil) {
+			delete(t.peers, id)
+		}
+	}
+	t.lastSweep = n
+}
+
+// buckets is a set of token buckets keyed by the chat ID.
+// Not safe for concurrent use.
+type buckets struct {
+	rate  float64
+	burst float64
+	m     map[int64]*bucket
+
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newBuckets(rate float64, burst int) *buckets {
+	if burst < 1 {
+		burst = 1
+	}
+	return &buckets{
+		rate:  rate,
+		burst: float64(burst),
+		m:     make(map[int64]*bucket),
+	}
+}
+
+// ready refills the bucket of the id and reports
+// whether it has at least one token.
+func (bs *buckets) ready(id int64, n time.Time) bool {
+	if bs.rate <= 0 {
+		return true
+	}
+
+	bs.sweep(n)
+
+	b, ok := bs.m[id]
+	if !ok {
+		b = &bucket{tokens: bs.burst, last: n}
+		bs.m[id] = b
+	}
+
+	b.tokens += n.Sub(b.last).Seconds() * bs.rate
+	if b.tokens > bs.burst {
+		b.tokens = bs.burst
+	}
+	b.last = n
+
+	return b.tokens >= 1
+}
+
+func (bs *buckets) take(id int64) {
+	if b, ok := bs.m[id]; ok {
+		b.tokens--
+	}
+}
+
+// interval returns the time an empty bucket is refilled in.
+func (bs *buckets) interval() time.Duration {
+	if bs.rate <= 0 {
+		return 0
+	}
+	return time.Duration(bs.burst / bs.rate * float64(time.Second))
+}
+
+// sweep drops the buckets which have been refilled completely,
+// so the map doesn't grow indefinitely.
+func (bs *buckets) sweep(n time.Time) {
+	interval := bs.interval()
+	if n.Sub(bs.lastSweep) < interval {
+		return
+	}
+
+	for id, b := range bs.m {
+		if n.Sub(b.last) >= interval {
+			delete(bs.m, id)
+		}
+	}
+	bs.lastSweep = n
+}
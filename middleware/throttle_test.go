@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+func TestThrottle(t *testing.T) {
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	var handled, throttled int
+	h := Throttle(ThrottleConfig{
+		UserRate:  1,
+		UserBurst: 2,
+		Strikes:   3,
+		IgnoreFor: time.Minute,
+		OnThrottle: func(c tele.Context) error {
+			throttled++
+			return nil
+		},
+	})(func(c tele.Context) error {
+		handled++
+		return nil
+	})
+
+	c := b.NewContext(tele.Update{Message: &tele.Message{
+		Sender: &tele.User{ID: 1},
+		Chat:   &tele.Chat{ID: 1},
+	}})
+
+	for i := 0; i < 4; i++ {
+		h(c)
+	}
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, 2, throttled)
+
+	// The third strike in a row escalates to ignoring.
+	h(c)
+	assert.Equal(t, 2, throttled)
+
+	clock = clock.Add(30 * time.Second)
+	h(c)
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, 2, throttled)
+
+	clock = clock.Add(time.Minute)
+	h(c)
+	assert.Equal(t, 3, handled)
+
+	other := b.NewContext(tele.Update{Message: &tele.Message{
+		Sender: &tele.User{ID: 2},
+		Chat:   &tele.Chat{ID: 2},
+	}})
+	h(other)
+	assert.Equal(t, 4, handled)
+}
+
+func TestThrottleChat(t *testing.T) {
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	var handled, throttled int
+	h := Throttle(ThrottleConfig{
+		ChatRate:  1,
+		Strikes:   1,
+		IgnoreFor: time.Hour,
+		OnThrottle: func(c tele.Context) error {
+			throttled++
+			return nil
+		},
+	})(func(c tele.Context) error {
+		handled++
+		return nil
+	})
+
+	chat := &tele.Chat{ID: -100}
+	for id := int64(1); id <= 3; id++ {
+		h(b.NewContext(tele.Update{Message: &tele.Message{
+			Sender: &tele.User{ID: id},
+			Chat:   chat,
+		}}))
+	}
+	assert.Equal(t, 1, handled)
+
+	// The busy chat isn't a strike of its users.
+	assert.Equal(t, 2, throttled)
+	clock = clock.Add(time.Second)
+	h(b.NewContext(tele.Update{Message: &tele.Message{
+		Sender: &tele.User{ID: 2},
+		Chat:   chat,
+	}}))
+	assert.Equal(t, 2, handled)
+
+	clock = clock.Add(time.Second)
+	h(b.NewContext(tele.Update{Message: &tele.Message{
+		Sender: &tele.User{ID: 4},
+		Chat:   chat,
+	}}))
+	assert.Equal(t, 3, handled)
+}
+
+func TestThrottlePeersSweep(t *testing.T) {
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	th := newThrottler(ThrottleConfig{
+		UserRate:  1,
+		Strikes:   3,
+		IgnoreFor: time.Minute,
+	})
+
+	for id := int64(1); id <= 3; id++ {
+		th.allow(id, 0)
+		assert.Equal(t, throttleRejected, th.allow(id, 0))
+	}
+	assert.Len(t, th.peers, 3)
+
+	clock = clock.Add(30 * time.Second)
+	assert.Equal(t, throttleAllowed, th.allow(4, 0))
+	assert.Len(t, th.peers, 3)
+
+	clock = clock.Add(30 * time.Second)
+	assert.Equal(t, throttleAllowed, th.allow(4, 0))
+	assert.Empty(t, th.peers)
+}
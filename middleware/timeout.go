@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+// ErrTimeout is returned by the Timeout middleware when
+// the handler exceeds its deadline.
+var ErrTimeout = errors.New("telebot: handler timed out")
+
+// ContextKey is the key the deadline-bound context.Context
+// is stored in the tele.Context by the Timeout middleware.
+const ContextKey = "middleware.context"
+
+// Deadline returns the deadline-bound context set by the Timeout
+// middleware. It returns context.Background if there is none.
+//
+// Usage:
+//
+//	b.Handle("/order", func(c tele.Context) error {
+//		order, err := api.FetchOrder(middleware.Deadline(c), c.Data())
+//		...
+//	}, middleware.Timeout(5*time.Second))
+func Deadline(c tele.Context) context.Context {
+	if ctx, ok := c.Get(ContextKey).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
+// Timeout returns a middleware that limits the handler execution
+// time. The handler gets a context cancelled after the duration,
+// see Deadline. If the handler doesn't return in time, the middleware
+// calls the optional fallback and returns an error wrapping ErrTimeout,
+// while the handler itself keeps running in the background.
+//
+// A panic of the handler is re-raised as a *PanicError carrying the
+// handler's stack trace, so the outer Recover reports it as is. If the
+// handler panics after the timeout, the *PanicError is passed to the
+// bot's OnError instead.
+func Timeout(d time.Duration, fallback ...tele.HandlerFunc) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			ctx, cancel := context.WithTimeout(Deadline(c), d)
+			c.Set(ContextKey, ctx)
+
+			done := make(chan error, 1)
+			panics := make(chan *PanicError, 1)
+
+			go func() {
+				defer cancel()
+				defer func() {
+					if r := recover(); r != nil {
+						// The stack is captured here, since it's
+						// lost once the panic is passed over.
+						panics <- newPanicError(r, c)
+					}
+				}()
+				done <- next(c)
+			}()
+
+			select {
+			case err := <-done:
+				return err
+			case perr := <-panics:
+				// Let the outer Recover middleware handle it.
+				panic(perr)
+			case <-ctx.Done():
+			}
+
+			// The handler may have finished right at the deadline.
+			select {
+			case err := <-done:
+				return err
+			default:
+			}
+
+			go func() {
+				select {
+				case <-done:
+				case perr := <-panics:
+					c.Bot().OnError(perr, c)
+				}
+			}()
+
+			err := fmt.Errorf("%w after %v", ErrTimeout, d)
+			if end := c.Endpoint(); end != "" {
+				err = fmt.Errorf("%w (endpoint %q)", err, strings.TrimLeft(end, "\a\f"))
+			}
+
+			if len(fallback) > 0 {
+				if ferr := fallback[0](c); ferr != nil {
+					c.Bot().OnError(ferr, c)
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// Fallback returns a handler to be used with the Timeout middleware.
+// It answers the pending callback with the optional response, so the
+// user's spinner doesn't hang, or sends what to the current chat otherwise.
+func Fallback(what interface{}, resp ...*tele.CallbackResponse) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		if c.Callback() != nil {
+			return c.Respond(resp...)
+		}
+		if what == nil {
+			return nil
+		}
+		return c.Send(what)
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tele "github.com/vadimpk/telebot"
+)
+
+func TestTimeout(t *testing.T) {
+	var fallback bool
+	cancelled := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	h := Timeout(10*time.Millisecond, func(c tele.Context) error {
+		fallback = true
+		return nil
+	})(func(c tele.Context) error {
+		<-Deadline(c).Done()
+		close(cancelled)
+		<-release
+		return nil
+	})
+
+	err := h(b.NewContext(tele.Update{}))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, fallback)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+}
+
+func TestTimeoutInTime(t *testing.T) {
+	h := Timeout(time.Second)(func(c tele.Context) error {
+		_, ok := Deadline(c).Deadline()
+		assert.True(t, ok)
+		return errors.New("handler error")
+	})
+
+	err := h(b.NewContext(tele.Update{}))
+	assert.EqualError(t, err, "handler error")
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	h := Recover(func(err error, c tele.Context) {
+		var perr *PanicError
+		require.True(t, errors.As(err, &perr))
+		assert.Equal(t, "timeout panic", perr.Value)
+		assert.Contains(t, string(perr.Stack), "panicHandler")
+	})(Timeout(time.Second)(panicHandler))
+
+	assert.NotPanics(t, func() {
+		h(b.NewContext(tele.Update{}))
+	})
+}
+
+func TestTimeoutLatePanic(t *testing.T) {
+	errs := make(chan error, 1)
+	b, err := tele.NewBot(tele.Settings{
+		Offline: true,
+		Handler: tele.NewHandler(tele.HandlerSettings{
+			OnError: func(err error, c tele.Context) { errs <- err },
+		}),
+	})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	h := Timeout(10 * time.Millisecond)(func(c tele.Context) error {
+		<-release
+		return panicHandler(c)
+	})
+
+	err = h(b.NewContext(tele.Update{}))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	close(release)
+
+	select {
+	case err := <-errs:
+		var perr *PanicError
+		require.True(t, errors.As(err, &perr))
+		assert.Equal(t, "timeout panic", perr.Value)
+		assert.Contains(t, string(perr.Stack), "panicHandler")
+	case <-time.After(5 * time.Second):
+		t.Fatal("late panic was not reported")
+	}
+}
+
+func panicHandler(c tele.Context) error {
+	panic("timeout panic")
+}
@@ -55,12 +55,14 @@ func Placeholder(text string) *SendOptions {
 // and re-using it somewhere or be using Option flags instead.
 type SendOptions struct {
 	// If the message is a reply, original message.
+	// Sent as reply_parameters, ReplyParams take precedence.
 	ReplyTo *Message
 
 	// See ReplyMarkup struct definition.
 	ReplyMarkup *ReplyMarkup
 
 	// For text messages, disables previews for links in this message.
+	// Sent as link_preview_options along with PreviewOptions.
 	DisableWebPagePreview bool
 
 	// Sends the message silently. iOS users will not receive a notification, Android users will receive a notification with no sound.
@@ -86,6 +88,9 @@ type SendOptions struct {
 
 	// ReplyParams Describes the message to reply to
 	ReplyParams *ReplyParams
+
+	// PreviewOptions describes the link preview generation.
+	PreviewOptions *PreviewOptions
 }
 
 func (og *SendOptions) copy() *SendOptions {
@@ -96,6 +101,43 @@ func (og *SendOptions) copy() *SendOptions {
 	return &cp
 }
 
+// replyParams merges the legacy ReplyTo and AllowWithoutReply
+// options into the reply parameters.
+func (og *SendOptions) replyParams() *ReplyParams {
+	var reply ReplyParams
+	switch {
+	case og.ReplyParams != nil:
+		reply = *og.ReplyParams
+	case og.ReplyTo != nil && og.ReplyTo.ID != 0:
+		reply.MessageID = og.ReplyTo.ID
+	default:
+		return nil
+	}
+
+	if og.AllowWithoutReply {
+		reply.AllowWithoutReply = true
+	}
+	return &reply
+}
+
+// previewOptions merges the legacy DisableWebPagePreview
+// option into the link preview options.
+func (og *SendOptions) previewOptions() *PreviewOptions {
+	var preview PreviewOptions
+	switch {
+	case og.PreviewOptions != nil:
+		preview = *og.PreviewOptions
+	case og.DisableWebPagePreview:
+	default:
+		return nil
+	}
+
+	if og.DisableWebPagePreview {
+		preview.Disabled = true
+	}
+	return &preview
+}
+
 func extractOptions(how []interface{}) *SendOptions {
 	opts := &SendOptions{}
 
@@ -109,6 +151,8 @@ func extractOptions(how []interface{}) *SendOptions {
 			}
 		case *ReplyParams:
 			opts.ReplyParams = opt
+		case *PreviewOptions:
+			opts.PreviewOptions = opt
 		case Option:
 			switch opt {
 			case NoPreview:
@@ -158,12 +202,14 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 		return
 	}
 
-	if opt.ReplyTo != nil && opt.ReplyTo.ID != 0 {
-		params["reply_to_message_id"] = strconv.Itoa(opt.ReplyTo.ID)
+	if reply := opt.replyParams(); reply != nil {
+		data, _ := json.Marshal(reply)
+		params["reply_parameters"] = string(data)
 	}
 
-	if opt.DisableWebPagePreview {
-		params["disable_web_page_preview"] = "true"
+	if preview := opt.previewOptions(); preview != nil {
+		data, _ := json.Marshal(preview)
+		params["link_preview_options"] = string(data)
 	}
 
 	if opt.DisableNotification {
@@ -185,14 +231,8 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 		}
 	}
 
-	if opt.AllowWithoutReply {
-		params["allow_sending_without_reply"] = "true"
-	}
-
 	if opt.ReplyMarkup != nil {
-		processButtons(opt.ReplyMarkup.InlineKeyboard)
-		replyMarkup, _ := json.Marshal(opt.ReplyMarkup)
-		params["reply_markup"] = string(replyMarkup)
+		params["reply_markup"] = marshalMarkup(opt.ReplyMarkup)
 	}
 
 	if opt.Protected {
@@ -208,49 +248,70 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 	}
 }
 
-func processButtons(keys [][]InlineButton) {
-	if keys == nil || len(keys) < 1 || len(keys[0]) < 1 {
-		return
+// processButtons returns a copy of the inline keyboard with the
+// callback data of the buttons turned into the form Telegram gets it.
+// The keyboard passed is never modified, so the same markup is safe
+// to be sent from many goroutines at once.
+func processButtons(keys [][]InlineButton) [][]InlineButton {
+	if len(keys) == 0 {
+		return keys
 	}
 
-	for i := range keys {
-		for j := range keys[i] {
-			key := &keys[i][j]
-			if key.Unique != "" {
-				// Format: "\f<callback_name>|<data>"
-				data := key.Data
-				if data == "" {
-					key.Data = "\f" + key.Unique
-				} else {
-					key.Data = "\f" + key.Unique + "|" + data
-				}
-			}
+	processed := make([][]InlineButton, len(keys))
+	for i, row := range keys {
+		processed[i] = make([]InlineButton, len(row))
+		for j := range row {
+			key := row[j]
+			key.Data = callbackData(&key)
+			key.Unique = ""
+			processed[i][j] = key
 		}
 	}
+	return processed
+}
+
+// marshalMarkup serializes the markup with its inline buttons processed.
+func marshalMarkup(markup *ReplyMarkup) string {
+	cp := *markup
+	cp.InlineKeyboard = processButtons(markup.InlineKeyboard)
+	data, _ := json.Marshal(&cp)
+	return string(data)
+}
+
+// callbackData returns the callback data of the button as it's sent.
+// Format: "\f<callback_name>|<data>"
+func callbackData(key *InlineButton) string {
+	if key.Unique == "" {
+		return key.Data
+	}
+	if key.Data == "" {
+		return "\f" + key.Unique
+	}
+	return "\f" + key.Unique + "|" + key.Data
 }
 
 // PreviewOptions describes the options used for link preview generation.
 type PreviewOptions struct {
 	// (Optional) True, if the link preview is disabled.
-	Disabled bool `json:"is_disabled"`
+	Disabled bool `json:"is_disabled,omitempty"`
 
 	// (Optional) URL to use for the link preview. If empty, then the first URL
 	// found in the message text will be used.
-	URL string `json:"url"`
+	URL string `json:"url,omitempty"`
 
 	// (Optional) True, if the media in the link preview is supposed to be shrunk;
 	// ignored if the URL isn't explicitly specified or media size change.
 	// isn't supported for the preview.
-	SmallMedia bool `json:"prefer_small_media"`
+	SmallMedia bool `json:"prefer_small_media,omitempty"`
 
 	// (Optional) True, if the media in the link preview is supposed to be enlarged;
 	// ignored if the URL isn't explicitly specified or media size change.
 	// isn't supported for the preview.
-	LargeMedia bool `json:"prefer_large_media"`
+	LargeMedia bool `json:"prefer_large_media,omitempty"`
 
 	// (Optional) True, if the link preview must be shown above the message text;
 	// otherwise, the link preview will be shown below the message text.
-	AboveText bool `json:"show_above_text"`
+	AboveText bool `json:"show_above_text,omitempty"`
 }
 
 func embedMessages(params map[string]string, msgs []Editable) {
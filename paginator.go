@@ -0,0 +1,181 @@
+package telebot
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Page is a single page of the list shown by Paginator.
+type Page struct {
+	// Text is the message text shown above the keyboard.
+	// If empty, Paginator.Text is used instead.
+	Text string
+
+	// Items are the buttons of the page items. It's up to you
+	// to handle them, e.g. with their own uniques.
+	Items []Btn
+
+	// Pages is the total number of pages.
+	Pages int
+}
+
+// PageLoader loads the page of the list. The page numbers start from zero.
+type PageLoader func(c Context, page int) (*Page, error)
+
+// Paginator is a reusable inline keyboard showing a list page by page,
+// with the navigation row below the items. It handles the navigation
+// callbacks on its own, editing the message in place.
+//
+// Example:
+//
+//	orders := &tele.Paginator{
+//		Unique: "orders",
+//		Text:   "Your orders:",
+//		Load: func(c tele.Context, page int) (*tele.Page, error) {
+//			list, total := db.Orders(c.Sender().ID, page*10, 10)
+//			...
+//			return &tele.Page{Items: btns, Pages: (total + 9) / 10}, nil
+//		},
+//	}
+//	orders.Register(handler)
+//
+//	handler.Handle("/orders", orders.Send)
+type Paginator struct {
+	// Unique is the callback unique of the navigation buttons.
+	Unique string
+
+	// Load loads the requested page.
+	Load PageLoader
+
+	// Text is the default message text.
+	Text string
+
+	// Columns is the number of item buttons per row. Defaults to 1.
+	Columns int
+
+	// Prev and Next are the labels of the navigation buttons.
+	// Default to "«" and "»".
+	Prev, Next string
+}
+
+// ErrNoPageLoader is returned when the paginator has no loader set.
+var ErrNoPageLoader = errors.New("telebot: paginator has no page loader")
+
+// CallbackUnique implements CallbackEndpoint.
+func (p *Paginator) CallbackUnique() string {
+	return "\f" + p.Unique
+}
+
+// Register registers the navigation callback handler of the paginator.
+func (p *Paginator) Register(h *Handler, m ...MiddlewareFunc) {
+	h.Handle(p, p.handle, m...)
+}
+
+// Send sends the first page of the list to the current chat.
+// The navigation buttons edit this message afterwards.
+func (p *Paginator) Send(c Context) error {
+	text, markup, err := p.Render(c, 0)
+	if err != nil {
+		return err
+	}
+	return c.Send(text, markup)
+}
+
+// Render loads the page and returns its text and the keyboard.
+// The page past the end of the list is replaced with the last one,
+// since the list may shrink while its message is still shown.
+func (p *Paginator) Render(c Context, page int) (string, *ReplyMarkup, error) {
+	if p.Load == nil {
+		return "", nil, ErrNoPageLoader
+	}
+
+	pg, err := p.Load(c, page)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if last := pg.Pages - 1; page > 0 && page > last {
+		if last < 0 {
+			last = 0
+		}
+		page = last
+
+		pg, err = p.Load(c, page)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	text := pg.Text
+	if text == "" {
+		text = p.Text
+	}
+
+	return text, p.markup(pg, page), nil
+}
+
+func (p *Paginator) markup(pg *Page, page int) *ReplyMarkup {
+	columns := p.Columns
+	if columns < 1 {
+		columns = 1
+	}
+
+	prev, next := p.Prev, p.Next
+	if prev == "" {
+		prev = "«"
+	}
+	if next == "" {
+		next = "»"
+	}
+
+	r := &ReplyMarkup{}
+	rows := r.Split(columns, pg.Items)
+
+	if pg.Pages > 1 {
+		var nav Row
+		if page > 0 {
+			nav = append(nav, r.Data(prev, p.Unique, strconv.Itoa(page-1)))
+		}
+		counter := strconv.Itoa(page+1) + "/" + strconv.Itoa(pg.Pages)
+		nav = append(nav, r.Data(counter, p.Unique))
+		if page < pg.Pages-1 {
+			nav = append(nav, r.Data(next, p.Unique, strconv.Itoa(page+1)))
+		}
+		rows = append(rows, nav)
+	}
+
+	r.Inline(rows...)
+	return r
+}
+
+func (p *Paginator) handle(c Context) error {
+	// The page counter button carries no data.
+	if c.Data() == "" {
+		return c.Respond()
+	}
+
+	page, err := strconv.Atoi(c.Data())
+	if err != nil || page < 0 {
+		return c.Respond()
+	}
+
+	text, markup, err := p.Render(c, page)
+	if err != nil {
+		// Stop the button's spinner anyway.
+		if rerr := c.Respond(); rerr != nil {
+			c.Bot().OnError(rerr, c)
+		}
+		return err
+	}
+
+	if text != "" {
+		err = c.Edit(text, markup)
+	} else {
+		_, err = c.Bot().EditReplyMarkup(c.Callback(), markup)
+	}
+	if err != nil && !errors.Is(err, ErrMessageNotModified) && !errors.Is(err, ErrSameMessageContent) {
+		return err
+	}
+
+	return c.Respond()
+}
@@ -0,0 +1,117 @@
+package telebot
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator(t *testing.T) {
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		if method == "answerCallbackQuery" {
+			return ""
+		}
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	var errs []error
+	h := NewHandler(HandlerSettings{
+		Synchronous: true,
+		OnError:     func(err error, c Context) { errs = append(errs, err) },
+	})
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true, Handler: h})
+	require.NoError(t, err)
+
+	var (
+		pages   = 3
+		loadErr error
+	)
+
+	r := &ReplyMarkup{}
+	p := &Paginator{
+		Unique:  "items",
+		Text:    "Items:",
+		Columns: 2,
+		Load: func(c Context, page int) (*Page, error) {
+			if loadErr != nil {
+				return nil, loadErr
+			}
+			var items []Btn
+			for i := page * 3; i < page*3+3 && i < 7; i++ {
+				items = append(items, r.Data("item "+strconv.Itoa(i), "item", strconv.Itoa(i)))
+			}
+			return &Page{Items: items, Pages: pages}, nil
+		},
+	}
+	p.Register(h)
+
+	text, markup, err := p.Render(nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Items:", text)
+	require.Len(t, markup.InlineKeyboard, 3)
+	assert.Len(t, markup.InlineKeyboard[0], 2)
+	assert.Len(t, markup.InlineKeyboard[1], 1)
+
+	nav := markup.InlineKeyboard[2]
+	require.Len(t, nav, 2)
+	assert.Equal(t, "1/3", nav[0].Text)
+	assert.Equal(t, "»", nav[1].Text)
+	assert.Equal(t, "1", nav[1].Data)
+
+	b.ProcessUpdate(Update{Callback: &Callback{
+		ID:      "1",
+		Data:    "\fitems|2",
+		Message: &Message{ID: 1, Chat: &Chat{ID: 1}},
+	}})
+
+	requests := api.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "editMessageText", requests[0].Method)
+	assert.Equal(t, "Items:", requests[0].Params["text"])
+	assert.Contains(t, requests[0].Params["reply_markup"], `"item 6"`)
+	assert.Contains(t, requests[0].Params["reply_markup"], `"3/3"`)
+	assert.NotContains(t, requests[0].Params["reply_markup"], `"»"`)
+	assert.Equal(t, "answerCallbackQuery", requests[1].Method)
+
+	api.Reset()
+	b.ProcessUpdate(Update{Callback: &Callback{
+		ID:      "2",
+		Data:    "\fitems",
+		Message: &Message{ID: 1, Chat: &Chat{ID: 1}},
+	}})
+
+	requests = api.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "answerCallbackQuery", requests[0].Method)
+
+	// The stale button past the end shows the last page.
+	api.Reset()
+	pages = 2
+	b.ProcessUpdate(Update{Callback: &Callback{
+		ID:      "3",
+		Data:    "\fitems|2",
+		Message: &Message{ID: 1, Chat: &Chat{ID: 1}},
+	}})
+
+	requests = api.Requests()
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[0].Params["reply_markup"], `"2/2"`)
+	assert.Contains(t, requests[0].Params["reply_markup"], `"item 3"`)
+
+	// The callback is answered even if the page fails to load.
+	api.Reset()
+	loadErr = errors.New("db is down")
+	b.ProcessUpdate(Update{Callback: &Callback{
+		ID:      "4",
+		Data:    "\fitems|1",
+		Message: &Message{ID: 1, Chat: &Chat{ID: 1}},
+	}})
+
+	requests = api.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "answerCallbackQuery", requests[0].Method)
+	assert.Equal(t, []error{loadErr}, errs)
+}
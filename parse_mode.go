@@ -0,0 +1,649 @@
+package telebot
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	htmlEscaper       = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	markdownEscaper   = newMarkdownEscaper("_*`[")
+	markdownV2Escaper = newMarkdownEscaper("_*[]()~`>#+-=|{}.!\\")
+	codeV2Escaper     = newMarkdownEscaper("`\\")
+	linkV2Escaper     = newMarkdownEscaper(")\\")
+)
+
+func newMarkdownEscaper(chars string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(chars))
+	for _, c := range chars {
+		pairs = append(pairs, string(c), "\\"+string(c))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// EscapeHTML escapes the text to be sent with ModeHTML.
+func EscapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// EscapeMarkdown escapes the text to be sent with the legacy ModeMarkdown.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// EscapeMarkdownV2 escapes the text to be sent with ModeMarkdownV2.
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// Escape escapes the text according to the parse mode,
+// so it's displayed by the clients exactly as is.
+func Escape(s string, mode ParseMode) string {
+	switch mode {
+	case ModeHTML:
+		return EscapeHTML(s)
+	case ModeMarkdown:
+		return EscapeMarkdown(s)
+	case ModeMarkdownV2:
+		return EscapeMarkdownV2(s)
+	default:
+		return s
+	}
+}
+
+// EntitiesToHTML converts the text with its entities into
+// the HTML-formatted text to be sent with ModeHTML. Overlapping
+// entities are split into properly nested tags, while the ones detected
+// by Telegram automatically, like EntityURL or EntityHashtag, are
+// left as plain text.
+//
+// Example:
+//
+//	text := tele.EntitiesToHTML(m.Text, m.Entities)
+//	b.Send(chat, text, tele.ModeHTML)
+func EntitiesToHTML(text string, entities Entities) string {
+	return renderEntities(text, entities, htmlRenderer{})
+}
+
+// EntitiesToMarkdownV2 converts the text with its entities into
+// the MarkdownV2-formatted text to be sent with ModeMarkdownV2.
+func EntitiesToMarkdownV2(text string, entities Entities) string {
+	return renderEntities(text, entities, &markdownV2Renderer{})
+}
+
+// entityRenderer renders the entity boundaries and text
+// segments in a specific parse mode.
+type entityRenderer interface {
+	open(sb *strings.Builder, e MessageEntity)
+	close(sb *strings.Builder, e MessageEntity)
+	text(sb *strings.Builder, s string, stack []MessageEntity)
+}
+
+func renderEntities(text string, entities Entities, r entityRenderer) string {
+	units := utf16.Encode([]rune(text))
+
+	sorted := make(Entities, 0, len(entities))
+	for _, e := range entities {
+		if e.Length > 0 && e.Offset >= 0 && e.Offset+e.Length <= len(units) {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset < sorted[j].Offset
+		}
+		return sorted[i].Length > sorted[j].Length
+	})
+
+	var (
+		sb    strings.Builder
+		stack []MessageEntity
+		next  int
+	)
+
+	end := func(e MessageEntity) int { return e.Offset + e.Length }
+
+	for pos := 0; pos <= len(units); {
+		// Close the entities ending here. The ones opened after them
+		// but ending later must be closed too and reopened afterwards.
+		for k := range stack {
+			if end(stack[k]) > pos {
+				continue
+			}
+
+			var reopen []MessageEntity
+			for i := len(stack) - 1; i >= k; i-- {
+				r.close(&sb, stack[i])
+				if end(stack[i]) > pos {
+					reopen = append([]MessageEntity{stack[i]}, reopen...)
+				}
+			}
+			stack = stack[:k]
+			for _, e := range reopen {
+				r.open(&sb, e)
+				stack = append(stack, e)
+			}
+			break
+		}
+
+		for next < len(sorted) && sorted[next].Offset == pos {
+			r.open(&sb, sorted[next])
+			stack = append(stack, sorted[next])
+			next++
+		}
+
+		if pos == len(units) {
+			break
+		}
+
+		boundary := len(units)
+		if next < len(sorted) && sorted[next].Offset < boundary {
+			boundary = sorted[next].Offset
+		}
+		for _, e := range stack {
+			if end(e) < boundary {
+				boundary = end(e)
+			}
+		}
+
+		r.text(&sb, string(utf16.Decode(units[pos:boundary])), stack)
+		pos = boundary
+	}
+
+	return sb.String()
+}
+
+type htmlRenderer struct{}
+
+func (htmlRenderer) open(sb *strings.Builder, e MessageEntity) {
+	switch e.Type {
+	case EntityBold:
+		sb.WriteString("<b>")
+	case EntityItalic:
+		sb.WriteString("<i>")
+	case EntityUnderline:
+		sb.WriteString("<u>")
+	case EntityStrikethrough:
+		sb.WriteString("<s>")
+	case EntitySpoiler:
+		sb.WriteString("<tg-spoiler>")
+	case EntityCode:
+		sb.WriteString("<code>")
+	case EntityCodeBlock:
+		if e.Language != "" {
+			sb.WriteString(`<pre><code class="language-` + EscapeHTML(e.Language) + `">`)
+		} else {
+			sb.WriteString("<pre>")
+		}
+	case EntityTextLink:
+		sb.WriteString(`<a href="` + EscapeHTML(e.URL) + `">`)
+	case EntityTMention:
+		if e.User != nil {
+			sb.WriteString(`<a href="tg://user?id=` + strconv.FormatInt(e.User.ID, 10) + `">`)
+		}
+	case EntityCustomEmoji:
+		sb.WriteString(`<tg-emoji emoji-id="` + EscapeHTML(e.CustomEmoji) + `">`)
+	case EntityBlockquote:
+		sb.WriteString("<blockquote>")
+	}
+}
+
+func (htmlRenderer) close(sb *strings.Builder, e MessageEntity) {
+	switch e.Type {
+	case EntityBold:
+		sb.WriteString("</b>")
+	case EntityItalic:
+		sb.WriteString("</i>")
+	case EntityUnderline:
+		sb.WriteString("</u>")
+	case EntityStrikethrough:
+		sb.WriteString("</s>")
+	case EntitySpoiler:
+		sb.WriteString("</tg-spoiler>")
+	case EntityCode:
+		sb.WriteString("</code>")
+	case EntityCodeBlock:
+		if e.Language != "" {
+			sb.WriteString("</code></pre>")
+		} else {
+			sb.WriteString("</pre>")
+		}
+	case EntityTextLink:
+		sb.WriteString("</a>")
+	case EntityTMention:
+		if e.User != nil {
+			sb.WriteString("</a>")
+		}
+	case EntityCustomEmoji:
+		sb.WriteString("</tg-emoji>")
+	case EntityBlockquote:
+		sb.WriteString("</blockquote>")
+	}
+}
+
+func (htmlRenderer) text(sb *strings.Builder, s string, _ []MessageEntity) {
+	sb.WriteString(EscapeHTML(s))
+}
+
+type markdownV2Renderer struct {
+	// last is the last marker character written, used to
+	// resolve the ambiguity between italic and underline.
+	last byte
+}
+
+func (r *markdownV2Renderer) marker(sb *strings.Builder, m string) {
+	if r.last == '_' && m[0] == '_' {
+		sb.WriteByte('\r')
+	}
+	sb.WriteString(m)
+	r.last = m[len(m)-1]
+}
+
+func (r *markdownV2Renderer) open(sb *strings.Builder, e MessageEntity) {
+	switch e.Type {
+	case EntityBold:
+		r.marker(sb, "*")
+	case EntityItalic:
+		r.marker(sb, "_")
+	case EntityUnderline:
+		r.marker(sb, "__")
+	case EntityStrikethrough:
+		r.marker(sb, "~")
+	case EntitySpoiler:
+		r.marker(sb, "||")
+	case EntityCode:
+		r.marker(sb, "`")
+	case EntityCodeBlock:
+		r.marker(sb, "```"+e.Language+"\n")
+	case EntityTextLink:
+		r.marker(sb, "[")
+	case EntityTMention:
+		if e.User != nil {
+			r.marker(sb, "[")
+		}
+	case EntityCustomEmoji:
+		r.marker(sb, "![")
+	case EntityBlockquote:
+		r.marker(sb, ">")
+	}
+}
+
+func (r *markdownV2Renderer) close(sb *strings.Builder, e MessageEntity) {
+	switch e.Type {
+	case EntityBold:
+		r.marker(sb, "*")
+	case EntityItalic:
+		r.marker(sb, "_")
+	case EntityUnderline:
+		r.marker(sb, "__")
+	case EntityStrikethrough:
+		r.marker(sb, "~")
+	case EntitySpoiler:
+		r.marker(sb, "||")
+	case EntityCode:
+		r.marker(sb, "`")
+	case EntityCodeBlock:
+		r.marker(sb, "\n```")
+	case EntityTextLink:
+		r.marker(sb, "]("+linkV2Escaper.Replace(e.URL)+")")
+	case EntityTMention:
+		if e.User != nil {
+			r.marker(sb, "](tg://user?id="+strconv.FormatInt(e.User.ID, 10)+")")
+		}
+	case EntityCustomEmoji:
+		r.marker(sb, "](tg://emoji?id="+linkV2Escaper.Replace(e.CustomEmoji)+")")
+	}
+}
+
+func (r *markdownV2Renderer) text(sb *strings.Builder, s string, stack []MessageEntity) {
+	quote, code := false, false
+	for _, e := range stack {
+		switch e.Type {
+		case EntityBlockquote:
+			quote = true
+		case EntityCode, EntityCodeBlock:
+			code = true
+		}
+	}
+
+	if code {
+		s = codeV2Escaper.Replace(s)
+	} else {
+		s = markdownV2Escaper.Replace(s)
+	}
+	if quote {
+		s = strings.ReplaceAll(s, "\n", "\n>")
+	}
+
+	sb.WriteString(s)
+	r.last = 0
+}
+
+// formatBuilder accumulates the parsed text along with
+// its entities, counting the length in UTF-16 code units.
+type formatBuilder struct {
+	sb       strings.Builder
+	length   int
+	entities Entities
+}
+
+func (fb *formatBuilder) write(s string) {
+	fb.sb.WriteString(s)
+	fb.length += utf16Len(s)
+}
+
+// open adds the entity starting at the current position
+// and returns its index to be passed to the close.
+func (fb *formatBuilder) open(e MessageEntity) int {
+	e.Offset = fb.length
+	fb.entities = append(fb.entities, e)
+	return len(fb.entities) - 1
+}
+
+func (fb *formatBuilder) close(i int) {
+	fb.entities[i].Length = fb.length - fb.entities[i].Offset
+}
+
+func (fb *formatBuilder) result() (string, Entities) {
+	var entities Entities
+	for _, e := range fb.entities {
+		if e.Length > 0 {
+			entities = append(entities, e)
+		}
+	}
+	return fb.sb.String(), entities
+}
+
+var (
+	htmlTagRx  = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:\s+[a-zA-Z-]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+))?)*)\s*/?>`)
+	htmlAttrRx = regexp.MustCompile(`([a-zA-Z-]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+)))?`)
+)
+
+// ParseHTML parses the text formatted with ModeHTML into
+// the plain text and its entities.
+func ParseHTML(s string) (string, Entities, error) {
+	type tag struct {
+		name  string
+		index int // -1 if the tag doesn't produce an entity
+	}
+
+	var (
+		fb    formatBuilder
+		stack []tag
+	)
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			fb.write(html.UnescapeString(s))
+			break
+		}
+		fb.write(html.UnescapeString(s[:i]))
+		s = s[i:]
+
+		m := htmlTagRx.FindStringSubmatch(s)
+		if m == nil {
+			return "", nil, fmt.Errorf("telebot: malformed html tag at %q", cut(s, 16))
+		}
+		s = s[len(m[0]):]
+
+		name := strings.ToLower(m[2])
+		if m[1] == "/" {
+			if len(stack) == 0 || stack[len(stack)-1].name != name {
+				return "", nil, fmt.Errorf("telebot: unexpected closing tag </%s>", name)
+			}
+			if t := stack[len(stack)-1]; t.index >= 0 {
+				fb.close(t.index)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		attrs := make(map[string]string)
+		for _, a := range htmlAttrRx.FindAllStringSubmatch(m[3], -1) {
+			attrs[strings.ToLower(a[1])] = html.UnescapeString(a[2] + a[3] + a[4])
+		}
+
+		e := MessageEntity{}
+		switch name {
+		case "b", "strong":
+			e.Type = EntityBold
+		case "i", "em":
+			e.Type = EntityItalic
+		case "u", "ins":
+			e.Type = EntityUnderline
+		case "s", "strike", "del":
+			e.Type = EntityStrikethrough
+		case "tg-spoiler":
+			e.Type = EntitySpoiler
+		case "span":
+			if attrs["class"] != "tg-spoiler" {
+				return "", nil, fmt.Errorf("telebot: unsupported span class %q", attrs["class"])
+			}
+			e.Type = EntitySpoiler
+		case "code":
+			// <pre><code class="language-go"> is a single code block.
+			if n := len(stack); n > 0 && stack[n-1].name == "pre" {
+				pre := &fb.entities[stack[n-1].index]
+				if pre.Offset == fb.length && pre.Language == "" {
+					pre.Language = strings.TrimPrefix(attrs["class"], "language-")
+					stack = append(stack, tag{name: name, index: -1})
+					continue
+				}
+			}
+			e.Type = EntityCode
+		case "pre":
+			e.Type = EntityCodeBlock
+		case "a":
+			href := attrs["href"]
+			if strings.HasPrefix(href, "tg://user?id=") {
+				id, err := strconv.ParseInt(strings.TrimPrefix(href, "tg://user?id="), 10, 64)
+				if err != nil {
+					return "", nil, fmt.Errorf("telebot: bad user mention %q", href)
+				}
+				e.Type = EntityTMention
+				e.User = &User{ID: id}
+			} else {
+				e.Type = EntityTextLink
+				e.URL = href
+			}
+		case "tg-emoji":
+			e.Type = EntityCustomEmoji
+			e.CustomEmoji = attrs["emoji-id"]
+		case "blockquote":
+			e.Type = EntityBlockquote
+		default:
+			return "", nil, fmt.Errorf("telebot: unsupported html tag <%s>", name)
+		}
+
+		stack = append(stack, tag{name: name, index: fb.open(e)})
+	}
+
+	if len(stack) > 0 {
+		return "", nil, fmt.Errorf("telebot: unclosed html tag <%s>", stack[len(stack)-1].name)
+	}
+
+	text, entities := fb.result()
+	return text, entities, nil
+}
+
+// ParseMarkdownV2 parses the text formatted with ModeMarkdownV2
+// into the plain text and its entities.
+func ParseMarkdownV2(s string) (string, Entities, error) {
+	type marker struct {
+		m     string
+		index int
+	}
+
+	var (
+		fb    formatBuilder
+		stack []marker
+		quote = -1
+	)
+
+	toggle := func(m string, t EntityType) {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].m == m {
+				fb.close(stack[i].index)
+				stack = append(stack[:i], stack[i+1:]...)
+				return
+			}
+		}
+		stack = append(stack, marker{m: m, index: fb.open(MessageEntity{Type: t})})
+	}
+
+	// readUntil reads the raw text until the unescaped end sequence,
+	// unescaping the characters preceded by a backslash.
+	readUntil := func(i int, end string) (string, int, bool) {
+		var sb strings.Builder
+		for i < len(s) {
+			switch {
+			case s[i] == '\\' && i+1 < len(s):
+				sb.WriteByte(s[i+1])
+				i += 2
+			case strings.HasPrefix(s[i:], end):
+				return sb.String(), i + len(end), true
+			default:
+				sb.WriteByte(s[i])
+				i++
+			}
+		}
+		return "", i, false
+	}
+
+	for i := 0; i < len(s); {
+		if i == 0 || s[i-1] == '\n' {
+			switch {
+			case s[i] == '>':
+				if quote < 0 {
+					quote = fb.open(MessageEntity{Type: EntityBlockquote})
+				}
+				i++
+				continue
+			case strings.HasPrefix(s[i:], "**>"):
+				if quote < 0 {
+					quote = fb.open(MessageEntity{Type: EntityBlockquote})
+				}
+				i += 3
+				continue
+			case quote >= 0:
+				// The trailing line break doesn't belong to the quote.
+				fb.close(quote)
+				fb.entities[quote].Length--
+				quote = -1
+			}
+		}
+
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			_, size := utf8.DecodeRuneInString(s[i+1:])
+			fb.write(s[i+1 : i+1+size])
+			i += 1 + size
+		case c == '\r':
+			i++
+		case c == '*':
+			toggle("*", EntityBold)
+			i++
+		case strings.HasPrefix(s[i:], "__"):
+			toggle("__", EntityUnderline)
+			i += 2
+		case c == '_':
+			toggle("_", EntityItalic)
+			i++
+		case c == '~':
+			toggle("~", EntityStrikethrough)
+			i++
+		case strings.HasPrefix(s[i:], "||"):
+			toggle("||", EntitySpoiler)
+			i += 2
+		case strings.HasPrefix(s[i:], "```"):
+			j := strings.IndexByte(s[i+3:], '\n')
+			if j < 0 {
+				return "", nil, fmt.Errorf("telebot: unclosed pre block")
+			}
+			lang := s[i+3 : i+3+j]
+
+			code, next, ok := readUntil(i+4+j, "```")
+			if !ok {
+				return "", nil, fmt.Errorf("telebot: unclosed pre block")
+			}
+
+			k := fb.open(MessageEntity{Type: EntityCodeBlock, Language: lang})
+			fb.write(strings.TrimSuffix(code, "\n"))
+			fb.close(k)
+			i = next
+		case c == '`':
+			code, next, ok := readUntil(i+1, "`")
+			if !ok {
+				return "", nil, fmt.Errorf("telebot: unclosed inline code")
+			}
+			k := fb.open(MessageEntity{Type: EntityCode})
+			fb.write(code)
+			fb.close(k)
+			i = next
+		case strings.HasPrefix(s[i:], "!["):
+			stack = append(stack, marker{m: "![", index: fb.open(MessageEntity{Type: EntityCustomEmoji})})
+			i += 2
+		case c == '[':
+			stack = append(stack, marker{m: "[", index: fb.open(MessageEntity{Type: EntityTextLink})})
+			i++
+		case c == ']' && len(stack) > 0 && strings.HasPrefix(s[i:], "]("):
+			top := stack[len(stack)-1]
+			if top.m != "[" && top.m != "![" {
+				return "", nil, fmt.Errorf("telebot: unexpected link end")
+			}
+
+			url, next, ok := readUntil(i+2, ")")
+			if !ok {
+				return "", nil, fmt.Errorf("telebot: unclosed link")
+			}
+
+			e := &fb.entities[top.index]
+			switch {
+			case top.m == "![":
+				e.CustomEmoji = strings.TrimPrefix(url, "tg://emoji?id=")
+			case strings.HasPrefix(url, "tg://user?id="):
+				id, err := strconv.ParseInt(strings.TrimPrefix(url, "tg://user?id="), 10, 64)
+				if err != nil {
+					return "", nil, fmt.Errorf("telebot: bad user mention %q", url)
+				}
+				e.Type = EntityTMention
+				e.User = &User{ID: id}
+			default:
+				e.URL = url
+			}
+
+			fb.close(top.index)
+			stack = stack[:len(stack)-1]
+			i = next
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			fb.write(s[i : i+size])
+			i += size
+		}
+	}
+
+	if len(stack) > 0 {
+		return "", nil, fmt.Errorf("telebot: unclosed markdown entity %q", stack[len(stack)-1].m)
+	}
+	if quote >= 0 {
+		fb.close(quote)
+	}
+
+	text, entities := fb.result()
+	return text, entities, nil
+}
+
+func cut(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package telebot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "a &lt;b&gt; &amp; &quot;c&quot;", Escape(`a <b> & "c"`, ModeHTML))
+	assert.Equal(t, `1\. \*a\* \_b\_ \(c\) \\`, Escape(`1. *a* _b_ (c) \`, ModeMarkdownV2))
+	assert.Equal(t, "\\*a\\* \\[b] (c)", Escape("*a* [b] (c)", ModeMarkdown))
+	assert.Equal(t, "*a*", Escape("*a*", ModeDefault))
+}
+
+func TestEntitiesConversion(t *testing.T) {
+	user := &User{ID: 42}
+
+	f := Format(
+		"Hi 👋 ", Mention(user, "Jon"), ", ",
+		Bold("bold ", Italic("it<a>lic"), Underline(" u_l")),
+		"\n", Pre("if a < b {}", "go"),
+		"\n", TextLink("https://go.dev/?a=(1)", "go"),
+		" ", CustomEmoji("123", "👍"), Spoiler(Strikethrough("x.")),
+		"\n", Blockquote("quote\nline"),
+	)
+
+	htmlText := EntitiesToHTML(f.Text, f.Entities)
+	assert.Equal(t, `Hi 👋 <a href="tg://user?id=42">Jon</a>, `+
+		`<b>bold <i>it&lt;a&gt;lic</i><u> u_l</u></b>`+"\n"+
+		`<pre><code class="language-go">if a &lt; b {}</code></pre>`+"\n"+
+		`<a href="https://go.dev/?a=(1)">go</a> <tg-emoji emoji-id="123">👍</tg-emoji>`+
+		`<tg-spoiler><s>x.</s></tg-spoiler>`+"\n"+
+		"<blockquote>quote\nline</blockquote>", htmlText)
+
+	mdText := EntitiesToMarkdownV2(f.Text, f.Entities)
+	assert.Equal(t, "Hi 👋 [Jon](tg://user?id=42), "+
+		"*bold _it<a\\>lic_\r__ u\\_l__*\n"+
+		"```go\nif a < b {}\n```\n"+
+		"[go](https://go.dev/?a=(1\\)) ![👍](tg://emoji?id=123)||~x\\.~||\n"+
+		">quote\n>line", mdText)
+
+	for name, parse := range map[string]func(string) (string, Entities, error){
+		"html":       ParseHTML,
+		"markdownv2": ParseMarkdownV2,
+	} {
+		var s string
+		if name == "html" {
+			s = htmlText
+		} else {
+			s = mdText
+		}
+
+		text, entities, err := parse(s)
+		require.NoError(t, err, name)
+		assert.Equal(t, f.Text, text, name)
+		assert.ElementsMatch(t, f.Entities, entities, name)
+	}
+}
+
+func TestEntitiesOverlapping(t *testing.T) {
+	entities := Entities{
+		{Type: EntityBold, Offset: 0, Length: 4},
+		{Type: EntityItalic, Offset: 2, Length: 4},
+	}
+	assert.Equal(t, "<b>ab<i>cd</i></b><i>ef</i>", EntitiesToHTML("abcdef", entities))
+}
+
+func TestParseHTML(t *testing.T) {
+	text, entities, err := ParseHTML(`<strong>a</strong> <span class="tg-spoiler">b</span> &#128075;<code>c</code>`)
+	require.NoError(t, err)
+	assert.Equal(t, "a b 👋c", text)
+	assert.Equal(t, Entities{
+		{Type: EntityBold, Offset: 0, Length: 1},
+		{Type: EntitySpoiler, Offset: 2, Length: 1},
+		{Type: EntityCode, Offset: 6, Length: 1},
+	}, entities)
+
+	_, _, err = ParseHTML("<b>a</i>")
+	assert.Error(t, err)
+	_, _, err = ParseHTML("<b>a")
+	assert.Error(t, err)
+	_, _, err = ParseHTML("<marquee>a</marquee>")
+	assert.Error(t, err)
+}
+
+func TestParseMarkdownV2(t *testing.T) {
+	text, entities, err := ParseMarkdownV2("*a \\* `c\\``* ___b_\r__")
+	require.NoError(t, err)
+	assert.Equal(t, "a * c` b", text)
+	assert.Equal(t, Entities{
+		{Type: EntityBold, Offset: 0, Length: 6},
+		{Type: EntityCode, Offset: 4, Length: 2},
+		{Type: EntityUnderline, Offset: 7, Length: 1},
+		{Type: EntityItalic, Offset: 7, Length: 1},
+	}, entities)
+
+	_, _, err = ParseMarkdownV2("*a")
+	assert.Error(t, err)
+	_, _, err = ParseMarkdownV2("`a")
+	assert.Error(t, err)
+}
+
+func TestEntityText(t *testing.T) {
+	m := &Message{Text: "a🎉b"}
+	assert.Equal(t, "🎉", m.EntityText(MessageEntity{Offset: 1, Length: 2}))
+	assert.Equal(t, "b", m.EntityText(MessageEntity{Offset: 3, Length: 1}))
+	assert.Equal(t, "🎉", m.EntityText(MessageEntity{Offset: 2, Length: 1}))
+	assert.Equal(t, "a🎉", m.EntityText(MessageEntity{Offset: 0, Length: 2}))
+	assert.Equal(t, "", m.EntityText(MessageEntity{Offset: 3, Length: 2}))
+	assert.Equal(t, "", m.EntityText(MessageEntity{Offset: 1, Length: -1}))
+	assert.Equal(t, "", m.EntityText(MessageEntity{Offset: -1, Length: 1}))
+
+	m = &Message{Caption: "🎉🎉"}
+	assert.Equal(t, "🎉", m.EntityText(MessageEntity{Offset: 2, Length: 2}))
+}
@@ -2,10 +2,22 @@ package telebot
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"strconv"
+	"time"
 )
 
+// Stars is the currency of the payments in Telegram Stars.
+const Stars = "XTR"
+
+// StarsSubscriptionPeriod is the only supported period of the
+// subscriptions paid in Telegram Stars, 30 days in seconds.
+const StarsSubscriptionPeriod = 2592000
+
+var ErrBadStarsInvoice = errors.New("telebot: bad Telegram Stars invoice")
+
 // ShippingQuery contains information about an incoming shipping query.
 type ShippingQuery struct {
 	Sender  *User           `json:"from"`
@@ -40,6 +52,31 @@ type Payment struct {
 	Order            Order  `json:"order_info"`
 	TelegramChargeID string `json:"telegram_payment_charge_id"`
 	ProviderChargeID string `json:"provider_payment_charge_id"`
+
+	// (Optional) Expiration date of the subscription paid in Telegram Stars,
+	// use SubscriptionExpirationDate() to get time.Time.
+	SubscriptionExpirationUnixtime int64 `json:"subscription_expiration_date"`
+
+	// (Optional) True, if the payment is a recurring payment for a subscription.
+	Recurring bool `json:"is_recurring"`
+
+	// (Optional) True, if the payment is the first payment for a subscription.
+	FirstRecurring bool `json:"is_first_recurring"`
+}
+
+// SubscriptionExpirationDate returns the moment of time when
+// the subscription paid in Telegram Stars expires in local time.
+func (p *Payment) SubscriptionExpirationDate() time.Time {
+	return time.Unix(p.SubscriptionExpirationUnixtime, 0)
+}
+
+// RefundedPayment contains basic information about a refunded payment.
+type RefundedPayment struct {
+	Currency         string `json:"currency"`
+	Total            int    `json:"total_amount"`
+	Payload          string `json:"invoice_payload"`
+	TelegramChargeID string `json:"telegram_payment_charge_id"`
+	ProviderChargeID string `json:"provider_payment_charge_id"`
 }
 
 // PreCheckoutQuery contains information about an incoming pre-checkout query.
@@ -92,6 +129,56 @@ type Invoice struct {
 	SendPhoneNumber     bool `json:"send_phone_number_to_provider"`
 	SendEmail           bool `json:"send_email_to_provider"`
 	Flexible            bool `json:"is_flexible"`
+
+	// SubscriptionPeriod makes the link created with CreateInvoiceLink
+	// charge the Telegram Stars subscription each period.
+	// Must be StarsSubscriptionPeriod if set.
+	SubscriptionPeriod int `json:"subscription_period,omitempty"`
+}
+
+// StarsInvoice returns the invoice for the amount of Telegram Stars.
+// Such invoices need no payment provider token.
+func StarsInvoice(title, description, payload string, amount int) Invoice {
+	return Invoice{
+		Title:       title,
+		Description: description,
+		Payload:     payload,
+		Currency:    Stars,
+		Prices:      []Price{{Label: title, Amount: amount}},
+	}
+}
+
+// StarsSubscription returns the invoice for the subscription charging
+// the amount of Telegram Stars every 30 days. Use it with CreateInvoiceLink.
+func StarsSubscription(title, description, payload string, amount int) Invoice {
+	i := StarsInvoice(title, description, payload, amount)
+	i.SubscriptionPeriod = StarsSubscriptionPeriod
+	return i
+}
+
+// validate checks the restrictions of the payments in Telegram Stars.
+func (i Invoice) validate() error {
+	if i.Currency != Stars {
+		if i.SubscriptionPeriod != 0 {
+			return fmt.Errorf("%w: subscriptions are only paid in Telegram Stars", ErrBadStarsInvoice)
+		}
+		return nil
+	}
+
+	switch {
+	case i.Token != "":
+		return fmt.Errorf("%w: provider token must be empty", ErrBadStarsInvoice)
+	case len(i.Prices) != 1:
+		return fmt.Errorf("%w: exactly one price is required", ErrBadStarsInvoice)
+	case i.MaxTipAmount != 0 || len(i.SuggestedTipAmounts) > 0:
+		return fmt.Errorf("%w: tips are not supported", ErrBadStarsInvoice)
+	case i.NeedName || i.NeedPhoneNumber || i.NeedEmail || i.NeedShippingAddress ||
+		i.SendPhoneNumber || i.SendEmail || i.Flexible:
+		return fmt.Errorf("%w: order info and shipping are not supported", ErrBadStarsInvoice)
+	case i.SubscriptionPeriod != 0 && i.SubscriptionPeriod != StarsSubscriptionPeriod:
+		return fmt.Errorf("%w: subscription period must be 30 days", ErrBadStarsInvoice)
+	}
+	return nil
 }
 
 func (i Invoice) params() map[string]string {
@@ -173,16 +260,18 @@ func (c Currency) ToTotal(total float64) int {
 
 // CreateInvoiceLink creates a link for a payment invoice.
 func (b *Bot) CreateInvoiceLink(i Invoice) (string, error) {
-	data, err := b.Raw("createInvoiceLink", i.params())
-	if err != nil {
+	if err := i.validate(); err != nil {
 		return "", err
 	}
 
-	var resp struct {
-		Result string
+	params := i.params()
+	if i.SubscriptionPeriod > 0 {
+		params["subscription_period"] = strconv.Itoa(i.SubscriptionPeriod)
 	}
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return "", wrapError(err)
+
+	var link string
+	if err := b.Call("createInvoiceLink", params, &link); err != nil {
+		return "", err
 	}
-	return resp.Result, nil
+	return link, nil
 }
@@ -0,0 +1,562 @@
+package telebot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Job is a delayed Bot API request. It's kept as the method name
+// with its parameters, so it survives the restarts in the JobStore.
+type Job struct {
+	ID     string            `json:"id"`
+	Method string            `json:"method"`
+	Params map[string]string `json:"params"`
+
+	// At is the time the job is due.
+	At time.Time `json:"at"`
+
+	// Every makes the job recurring with the interval.
+	Every time.Duration `json:"every,omitempty"`
+
+	// Running marks the job being executed. Such jobs found on start
+	// were interrupted by a crash and are executed again.
+	Running bool `json:"running,omitempty"`
+}
+
+// JobStore persists the scheduled jobs.
+type JobStore interface {
+	// Save creates or replaces the job.
+	Save(job Job) error
+
+	// Delete removes the job.
+	Delete(id string) error
+
+	// Load returns all the stored jobs.
+	Load() ([]Job, error)
+}
+
+var (
+	ErrJobNotFound = errors.New("telebot: scheduled job not found")
+	ErrJobUpload   = errors.New("telebot: scheduled job can't upload files, use file IDs or URLs")
+)
+
+// Scheduler executes the delayed sends, edits and deletes, and the
+// recurring jobs. Use a persistent JobStore to keep the jobs across
+// the restarts, and only run one scheduler per store.
+//
+// Each job is executed exactly once: it's marked as running in the store
+// before its request and is only removed or rescheduled after it, so the
+// job interrupted by a crash or a restart is executed again on start.
+// The repeated edits and deletes that turn out to be done already are
+// treated as successful. The one case that can't be told apart is the
+// crash right after Telegram got a send: the Bot API has no idempotency
+// keys, so such a message is sent again.
+//
+// Example:
+//
+//	s, err := tele.NewScheduler(b, tele.NewFileJobStore("jobs.json"), onJobError)
+//	if err != nil { ... }
+//	s.Start()
+//	defer s.Stop()
+//
+//	s.SendAt(time.Now().Add(time.Hour), chat, "Reminder!")
+type Scheduler struct {
+	// OnError is called on the failed jobs.
+	// Defaults to the bot's error handler.
+	OnError func(error, Job)
+
+	b     *Bot
+	store JobStore
+
+	mu   sync.Mutex
+	jobs map[string]Job
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewScheduler creates the scheduler loading the stored jobs.
+// A nil store keeps the jobs in memory only. The optional onError
+// sets OnError.
+func NewScheduler(b *Bot, store JobStore, onError ...func(error, Job)) (*Scheduler, error) {
+	if store == nil {
+		store = NewMemoryJobStore()
+	}
+
+	s := &Scheduler{
+		b:     b,
+		store: store,
+		jobs:  make(map[string]Job),
+		wake:  make(chan struct{}, 1),
+	}
+	if len(onError) > 0 {
+		s.OnError = onError[0]
+	}
+
+	jobs, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	// The interrupted jobs stay marked as running,
+	// so their repeated requests are recognized.
+	for _, job := range jobs {
+		s.jobs[job.ID] = job
+	}
+
+	return s, nil
+}
+
+// Start starts executing the jobs in the background.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Stop stops the scheduler, waiting for the current job to finish.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Schedule adds the job, generating its ID if empty, and returns the ID.
+func (s *Scheduler) Schedule(job Job) (string, error) {
+	if job.ID == "" {
+		id, err := newJobID()
+		if err != nil {
+			return "", err
+		}
+		job.ID = id
+	}
+	job.Running = false
+
+	if err := s.store.Save(job); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	s.notify()
+	return job.ID, nil
+}
+
+// Cancel removes the scheduled job.
+func (s *Scheduler) Cancel(id string) error {
+	s.mu.Lock()
+	_, ok := s.jobs[id]
+	delete(s.jobs, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	s.notify()
+	return s.store.Delete(id)
+}
+
+// Jobs returns the scheduled jobs.
+func (s *Scheduler) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// SendAt schedules the message to be sent at the time. It takes
+// the same arguments as Bot.Send, but the media must be given by
+// the file IDs or URLs, see ErrJobUpload.
+func (s *Scheduler) SendAt(at time.Time, to Recipient, what interface{}, opts ...interface{}) (string, error) {
+	return s.SendEvery(at, 0, to, what, opts...)
+}
+
+// SendEvery schedules the message to be sent
+// every interval, starting from the time.
+func (s *Scheduler) SendEvery(at time.Time, every time.Duration, to Recipient, what interface{}, opts ...interface{}) (string, error) {
+	job, err := s.record(func(b *Bot) error {
+		_, err := b.Send(to, what, opts...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	job.At, job.Every = at, every
+	return s.Schedule(job)
+}
+
+// EditAt schedules the message text to be edited at the time.
+func (s *Scheduler) EditAt(at time.Time, msg Editable, text string, opts ...interface{}) (string, error) {
+	params := map[string]string{"text": text}
+	embedEditable(params, msg)
+	s.b.embedSendOptions(params, extractOptions(opts))
+
+	return s.Schedule(Job{Method: "editMessageText", Params: params, At: at})
+}
+
+// DeleteAt schedules the message to be deleted at the time.
+func (s *Scheduler) DeleteAt(at time.Time, msg Editable) (string, error) {
+	msgID, chatID := msg.MessageSig()
+	params := map[string]string{
+		"chat_id":    strconv.FormatInt(chatID, 10),
+		"message_id": msgID,
+	}
+
+	return s.Schedule(Job{Method: "deleteMessage", Params: params, At: at})
+}
+
+// record returns the request made by fn as a job, instead of sending it.
+func (s *Scheduler) record(fn func(*Bot) error) (Job, error) {
+	var job Job
+
+	b := *s.b
+	b.client = &http.Client{Transport: &jobRecorder{job: &job}}
+
+	err := fn(&b)
+	if !errors.Is(err, errJobRecorded) {
+		if err == nil {
+			err = ErrUnsupportedWhat
+		}
+		return Job{}, err
+	}
+	return job, nil
+}
+
+var errJobRecorded = errors.New("telebot: job recorded")
+
+// jobRecorder is the transport recording the request into the job.
+type jobRecorder struct {
+	job *Job
+}
+
+func (r *jobRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer req.Body.Close()
+
+	if req.Header.Get("Content-Type") != "application/json" {
+		return nil, ErrJobUpload
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(req.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var s string
+		if json.Unmarshal(v, &s) != nil {
+			s = string(v)
+		}
+		params[k] = s
+	}
+
+	r.job.Method = path.Base(req.URL.Path)
+	r.job.Params = params
+	return nil, errJobRecorded
+}
+
+func embedEditable(params map[string]string, msg Editable) {
+	msgID, chatID := msg.MessageSig()
+	if chatID == 0 { // if inline message
+		params["inline_message_id"] = msgID
+	} else {
+		params["chat_id"] = strconv.FormatInt(chatID, 10)
+		params["message_id"] = msgID
+	}
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run(stop, done chan struct{}) {
+	defer close(done)
+
+	for {
+		due, next := s.due()
+		for _, job := range due {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.execute(job)
+		}
+		if len(due) > 0 {
+			continue
+		}
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+
+		select {
+		case <-stop:
+		case <-s.wake:
+		case <-fire:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
+}
+
+// due returns the jobs due now and the time of the next one.
+func (s *Scheduler) due() (due []Job, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, job := range s.jobs {
+		if !job.At.After(now) {
+			due = append(due, job)
+		} else if next.IsZero() || job.At.Before(next) {
+			next = job.At
+		}
+	}
+	return due, next
+}
+
+func (s *Scheduler) execute(job Job) {
+	s.mu.Lock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		s.mu.Unlock()
+		return // cancelled meanwhile
+	}
+
+	if job.Every > 0 {
+		next := job
+		next.Running = false
+		for now := time.Now(); !next.At.After(now); {
+			next.At = next.At.Add(job.Every)
+		}
+		s.jobs[job.ID] = next
+	} else {
+		delete(s.jobs, job.ID)
+	}
+	s.mu.Unlock()
+
+	running := job
+	running.Running = true
+	if err := s.store.Save(running); err != nil {
+		s.fail(err, job)
+		return
+	}
+
+	// The recurring job cancelled meanwhile may have been deleted
+	// from the store before it was saved, don't let it come back.
+	if job.Every > 0 && !s.scheduled(job.ID) {
+		if err := s.store.Delete(job.ID); err != nil {
+			s.fail(err, job)
+		}
+		return
+	}
+
+	_, err := s.b.Raw(job.Method, job.Params)
+	if err != nil && !(job.Running && doneAlready(err)) {
+		s.fail(err, job)
+	}
+
+	if err := s.settle(job.ID); err != nil {
+		s.fail(err, job)
+	}
+}
+
+// settle saves the rescheduled job or deletes the executed one. The job
+// cancelled while being saved is deleted again, so it doesn't come back.
+func (s *Scheduler) settle(id string) error {
+	s.mu.Lock()
+	next, ok := s.jobs[id]
+	s.mu.Unlock()
+
+	if ok {
+		if err := s.store.Save(next); err != nil {
+			return err
+		}
+		if s.scheduled(id) {
+			return nil
+		}
+	}
+	return s.store.Delete(id)
+}
+
+// doneAlready reports whether the error means the repeated
+// request of the interrupted job was done by the first one.
+func doneAlready(err error) bool {
+	return errors.Is(err, ErrMessageNotModified) ||
+		errors.Is(err, ErrSameMessageContent) ||
+		errors.Is(err, ErrNotFoundToDelete)
+}
+
+func (s *Scheduler) scheduled(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.jobs[id]
+	return ok
+}
+
+func (s *Scheduler) fail(err error, job Job) {
+	if s.OnError != nil {
+		s.OnError(err, job)
+	} else {
+		s.b.OnError(err, nil)
+	}
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", wrapError(err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// NewMemoryJobStore returns the JobStore keeping the jobs in memory.
+func NewMemoryJobStore() JobStore {
+	return &memoryJobStore{jobs: make(map[string]Job)}
+}
+
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func (m *memoryJobStore) Save(job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *memoryJobStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+func (m *memoryJobStore) Load() ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// NewFileJobStore returns the JobStore keeping the jobs in the JSON file.
+// The file is rewritten atomically on each change.
+func NewFileJobStore(path string) JobStore {
+	return &fileJobStore{path: path}
+}
+
+type fileJobStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *fileJobStore) Save(job Job) error {
+	return f.update(func(jobs map[string]Job) { jobs[job.ID] = job })
+}
+
+func (f *fileJobStore) Delete(id string) error {
+	return f.update(func(jobs map[string]Job) { delete(jobs, id) })
+}
+
+func (f *fileJobStore) Load() ([]Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	jobs, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job)
+	}
+	return list, nil
+}
+
+func (f *fileJobStore) read() (map[string]Job, error) {
+	jobs := make(map[string]Job)
+
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return jobs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, wrapError(err)
+	}
+	return jobs, nil
+}
+
+func (f *fileJobStore) update(fn func(map[string]Job)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	jobs, err := f.read()
+	if err != nil {
+		return err
+	}
+	fn(jobs)
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
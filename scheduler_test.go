@@ -0,0 +1,196 @@
+package telebot
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	calls := make(chan string, 100)
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		select {
+		case calls <- method + ":" + params["text"] + params["message_id"]:
+		default:
+		}
+		if method == "deleteMessage" && params["message_id"] == "5" {
+			return `{"ok":false,"error_code":400,"description":"Bad Request: message to delete not found"}`
+		}
+		return ""
+	})
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	store := NewFileJobStore(filepath.Join(t.TempDir(), "jobs.json"))
+
+	now := time.Now()
+
+	// The jobs interrupted by the previous run are executed again.
+	// The delete turns out to be done already, which isn't an error.
+	require.NoError(t, store.Save(Job{
+		ID:      "interrupted-send",
+		Method:  "sendMessage",
+		Params:  map[string]string{"chat_id": "1", "text": "interrupted"},
+		At:      now,
+		Running: true,
+	}))
+	require.NoError(t, store.Save(Job{
+		ID:      "interrupted-delete",
+		Method:  "deleteMessage",
+		Params:  map[string]string{"chat_id": "1", "message_id": "5"},
+		At:      now,
+		Running: true,
+	}))
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	onError := func(err error, job Job) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	s, err := NewScheduler(b, store, onError)
+	require.NoError(t, err)
+	assert.Len(t, s.Jobs(), 2)
+
+	chat := &Chat{ID: 1}
+
+	_, err = s.SendAt(now.Add(30*time.Millisecond), chat, "once")
+	require.NoError(t, err)
+	_, err = s.SendEvery(now.Add(10*time.Millisecond), 40*time.Millisecond, chat, "every")
+	require.NoError(t, err)
+	_, err = s.DeleteAt(now.Add(20*time.Millisecond), &Message{ID: 1, Chat: chat})
+	require.NoError(t, err)
+	cancelled, err := s.SendAt(now.Add(30*time.Millisecond), chat, "cancelled")
+	require.NoError(t, err)
+	require.NoError(t, s.Cancel(cancelled))
+	assert.ErrorIs(t, s.Cancel(cancelled), ErrJobNotFound)
+
+	// The jobs survive the restart.
+	s, err = NewScheduler(b, store, onError)
+	require.NoError(t, err)
+	require.Len(t, s.Jobs(), 5)
+
+	s.Start()
+
+	seen := make(map[string]int)
+	deadline := time.After(5 * time.Second)
+	for seen["sendMessage:once"] == 0 || seen["deleteMessage:1"] == 0 || seen["sendMessage:every"] < 2 ||
+		seen["sendMessage:interrupted"] == 0 || seen["deleteMessage:5"] == 0 {
+		select {
+		case call := <-calls:
+			seen[call]++
+		case <-deadline:
+			t.Fatalf("jobs were not executed: %v", seen)
+		}
+	}
+	s.Stop()
+
+	for _, req := range api.Requests() {
+		assert.NotEqual(t, "cancelled", req.Params["text"])
+	}
+	assert.Equal(t, 1, seen["sendMessage:once"])
+	assert.Equal(t, 1, seen["sendMessage:interrupted"])
+
+	mu.Lock()
+	assert.Empty(t, errs)
+	mu.Unlock()
+
+	jobs, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "every", jobs[0].Params["text"])
+	assert.True(t, jobs[0].At.After(now.Add(50*time.Millisecond)))
+}
+
+// blockingJobStore blocks the first Save once armed,
+// until the release channel is closed.
+type blockingJobStore struct {
+	JobStore
+	saving, release chan struct{}
+}
+
+func (s *blockingJobStore) Save(job Job) error {
+	if s.saving != nil {
+		close(s.saving)
+		s.saving = nil
+		<-s.release
+	}
+	return s.JobStore.Save(job)
+}
+
+func TestSchedulerCancelWhileExecuting(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	store := &blockingJobStore{JobStore: NewMemoryJobStore()}
+	s, err := NewScheduler(b, store)
+	require.NoError(t, err)
+
+	id, err := s.SendEvery(time.Now(), time.Hour, &Chat{ID: 1}, "every")
+	require.NoError(t, err)
+
+	saving, release := make(chan struct{}), make(chan struct{})
+	store.saving, store.release = saving, release
+
+	s.Start()
+	defer s.Stop()
+
+	select {
+	case <-saving:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not executed")
+	}
+
+	// Cancel the job while its next run is being saved.
+	require.NoError(t, s.Cancel(id))
+	close(release)
+	s.Stop()
+
+	jobs, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+	assert.Empty(t, api.Requests())
+}
+
+func TestSchedulerSendMedia(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	s, err := NewScheduler(b, nil)
+	require.NoError(t, err)
+
+	photo := &Photo{File: File{FileID: "photo"}, Caption: "Photo"}
+	id, err := s.SendAt(time.Now().Add(time.Hour), &Chat{ID: 1}, photo, Silent)
+	require.NoError(t, err)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, id, jobs[0].ID)
+	assert.Equal(t, "sendPhoto", jobs[0].Method)
+	assert.Equal(t, "1", jobs[0].Params["chat_id"])
+	assert.Equal(t, "photo", jobs[0].Params["photo"])
+	assert.Equal(t, "Photo", jobs[0].Params["caption"])
+	assert.Equal(t, "true", jobs[0].Params["disable_notification"])
+
+	_, err = s.SendAt(time.Now(), &Chat{ID: 1}, &Photo{File: FromReader(strings.NewReader("photo"))})
+	assert.ErrorIs(t, err, ErrJobUpload)
+	_, err = s.SendAt(time.Now(), &Chat{ID: 1}, 42)
+	assert.ErrorIs(t, err, ErrUnsupportedWhat)
+
+	assert.Len(t, s.Jobs(), 1)
+	assert.Empty(t, api.Requests())
+}
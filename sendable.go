@@ -316,8 +316,38 @@ func (v *Venue) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 	return extractMessage(data)
 }
 
+// Send delivers contact through bot b to recipient.
+func (c *Contact) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
+	if len(c.VCard) > MaxVCardSize {
+		return nil, ErrTooLongVCard
+	}
+
+	params := map[string]string{
+		"chat_id":      to.Recipient(),
+		"phone_number": c.PhoneNumber,
+		"first_name":   c.FirstName,
+		"last_name":    c.LastName,
+		"vcard":        c.VCard,
+	}
+	b.embedSendOptions(params, opt)
+
+	data, err := b.Raw("sendContact", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractMessage(data)
+}
+
 // Send delivers invoice through bot b to recipient.
 func (i *Invoice) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
+	if err := i.validate(); err != nil {
+		return nil, err
+	}
+	if i.SubscriptionPeriod != 0 {
+		return nil, fmt.Errorf("%w: subscriptions are only created with CreateInvoiceLink", ErrBadStarsInvoice)
+	}
+
 	params := i.params()
 	params["chat_id"] = to.Recipient()
 	b.embedSendOptions(params, opt)
@@ -0,0 +1,349 @@
+package telebot
+
+import (
+	"errors"
+	"unicode/utf16"
+)
+
+// Telegram limits in UTF-16 code units, after the entities parsing.
+const (
+	MaxMessageLength = 4096
+	MaxCaptionLength = 1024
+)
+
+// ErrSplitMarkdown is returned by SendSplit for the legacy ModeMarkdown
+// texts, which can't be split without breaking the markup.
+var ErrSplitMarkdown = errors.New("telebot: legacy markdown can't be split, use ModeMarkdownV2 or ModeHTML")
+
+// Split splits the formatted text into parts of at most limit UTF-16
+// code units. It prefers cutting at paragraph, then line, then word
+// boundaries, dropping the whitespace the text is cut at. Styling
+// entities crossing the cut are continued in the next part, while
+// the atomic ones, like mentions, links, code or custom emoji, are
+// never cut, unless they don't fit into the limit themselves.
+func (f *Formatted) Split(limit int) []*Formatted {
+	units := utf16.Encode([]rune(f.Text))
+
+	var parts []*Formatted
+	for start := 0; ; {
+		if len(units)-start <= limit {
+			if start < len(units) || len(parts) == 0 {
+				parts = append(parts, f.slice(units, start, len(units)))
+			}
+			return parts
+		}
+
+		cut, next := splitPoint(units, f.Entities, start, start+limit)
+		parts = append(parts, f.slice(units, start, cut))
+		start = next
+	}
+}
+
+// cut cuts the head of at most limit UTF-16 code units off the text.
+func (f *Formatted) cut(limit int) (head, rest *Formatted) {
+	units := utf16.Encode([]rune(f.Text))
+	if len(units) <= limit {
+		return f, &Formatted{}
+	}
+
+	cut, next := splitPoint(units, f.Entities, 0, limit)
+	return f.slice(units, 0, cut), f.slice(units, next, len(units))
+}
+
+// splitPoint returns where the part [start, end) should be cut
+// and where the next part should start.
+func splitPoint(units []uint16, entities Entities, start, end int) (cut, next int) {
+	limit := end - start
+
+	cut = -1
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		for to := end; cut < 0; {
+			i := lastIndexUnits(units[start:to], sep)
+			if i <= 0 {
+				break
+			}
+
+			// Look for the separator before the atomic entity
+			// containing this one, unless there is nothing before.
+			e := atomicEntityAt(entities, start+i, limit)
+			if e == nil {
+				cut = start + i
+			} else if e.Offset <= start {
+				break
+			} else {
+				to = e.Offset
+			}
+		}
+		if cut >= 0 {
+			break
+		}
+	}
+
+	if cut < 0 {
+		cut = end
+		for e := atomicEntityAt(entities, cut, limit); e != nil; e = atomicEntityAt(entities, cut, limit) {
+			cut = e.Offset
+		}
+		if utf16.IsSurrogate(rune(units[cut-1])) && units[cut-1] < 0xdc00 {
+			cut--
+		}
+	}
+
+	next = cut
+	for next < len(units) && (units[next] == '\n' || units[next] == ' ') {
+		next++
+	}
+	return cut, next
+}
+
+// atomicEntityAt returns the atomic entity the position is strictly
+// inside of. The entities longer than the limit are ignored, since
+// they can't be kept whole anyway.
+func atomicEntityAt(entities Entities, pos, limit int) *MessageEntity {
+	for i, e := range entities {
+		if atomicEntity(e.Type) && e.Length <= limit && e.Offset < pos && pos < e.Offset+e.Length {
+			return &entities[i]
+		}
+	}
+	return nil
+}
+
+func lastIndexUnits(units []uint16, sep string) int {
+	n := len(sep)
+outer:
+	for i := len(units) - n; i >= 0; i-- {
+		for j := 0; j < n; j++ {
+			if units[i+j] != uint16(sep[j]) {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// atomicEntity reports whether the entity would change its meaning
+// if cut, unlike the styling ones which are simply continued.
+func atomicEntity(t EntityType) bool {
+	switch t {
+	case EntityBold, EntityItalic, EntityUnderline, EntityStrikethrough,
+		EntitySpoiler, EntityBlockquote:
+		return false
+	default:
+		return true
+	}
+}
+
+// slice returns the part [start, end) of the text with
+// its entities clipped and shifted to the part's bounds.
+func (f *Formatted) slice(units []uint16, start, end int) *Formatted {
+	part := &Formatted{Text: string(utf16.Decode(units[start:end]))}
+	for _, e := range f.Entities {
+		from, to := e.Offset, e.Offset+e.Length
+		if from < start {
+			from = start
+		}
+		if to > end {
+			to = end
+		}
+		if from >= to {
+			continue
+		}
+
+		e.Offset, e.Length = from-start, to-from
+		part.Entities = append(part.Entities, e)
+	}
+	return part
+}
+
+// SendSplit works like Send, but splits the texts exceeding
+// MaxMessageLength into several messages, see Formatted.Split.
+// The caption exceeding MaxCaptionLength is cut, and the rest of it
+// follows the media in the separate text messages. Texts formatted with
+// ModeHTML or ModeMarkdownV2 are parsed first, so the parts never break
+// the markup. The reply markup is attached to the last part only, the
+// reply parameters to the first one. It returns all the sent messages.
+//
+// Example:
+//
+//	msgs, err := b.SendSplit(chat, report, tele.ModeHTML, markup)
+func (b *Bot) SendSplit(to Recipient, what interface{}, opts ...interface{}) ([]Message, error) {
+	if to == nil {
+		return nil, ErrBadRecipient
+	}
+
+	sendOpts := extractOptions(opts)
+	if err := sendOpts.ReplyMarkup.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		media Sendable
+		text  *Formatted
+	)
+
+	switch object := what.(type) {
+	case string:
+		f, err := b.formatted(object, sendOpts)
+		if err != nil {
+			return nil, err
+		}
+		text = f
+	case *Formatted:
+		text = object
+	case Sendable:
+		caption, ok := captionOf(object)
+		if !ok || utf16Len(caption) <= MaxCaptionLength {
+			msg, err := object.Send(b, to, sendOpts)
+			if err != nil {
+				return nil, err
+			}
+			return []Message{*msg}, nil
+		}
+
+		f, err := b.formatted(caption, sendOpts)
+		if err != nil {
+			return nil, err
+		}
+		media, text = object, f
+	default:
+		return nil, ErrUnsupportedWhat
+	}
+
+	var parts []*Formatted
+	if media != nil {
+		head, rest := text.cut(MaxCaptionLength)
+		parts = []*Formatted{head}
+		if rest.Text != "" {
+			parts = append(parts, rest.Split(MaxMessageLength)...)
+		}
+	} else {
+		parts = text.Split(MaxMessageLength)
+	}
+
+	var msgs []Message
+	for i, part := range parts {
+		opt := sendOpts.copy()
+		opt.ParseMode = ModeDefault
+		opt.Entities = nil
+		if i > 0 {
+			opt.ReplyTo = nil
+			opt.ReplyParams = nil
+		}
+		if i < len(parts)-1 {
+			opt.ReplyMarkup = nil
+		}
+
+		var (
+			msg *Message
+			err error
+		)
+		if i == 0 && media != nil {
+			caption := part.Text
+			if len(part.Entities) > 0 {
+				opt.Entities = part.Entities
+			} else if mode := b.parseMode(sendOpts); mode != ModeDefault {
+				// The default parse mode of the bot is applied to
+				// the caption without entities, so keep it intact.
+				caption = Escape(part.Text, mode)
+				opt.ParseMode = mode
+			}
+			// The head is sent on a copy, the caller's media
+			// may be in use by the other goroutines.
+			msg, err = withCaption(media, caption).Send(b, to, opt)
+		} else {
+			msg, err = part.Send(b, to, opt)
+		}
+		if err != nil {
+			return msgs, err
+		}
+
+		msgs = append(msgs, *msg)
+	}
+
+	return msgs, nil
+}
+
+// formatted parses the text according to the send options.
+func (b *Bot) formatted(text string, opt *SendOptions) (*Formatted, error) {
+	if len(opt.Entities) > 0 {
+		return &Formatted{Text: text, Entities: opt.Entities}, nil
+	}
+
+	var (
+		entities Entities
+		err      error
+	)
+
+	switch b.parseMode(opt) {
+	case ModeHTML:
+		text, entities, err = ParseHTML(text)
+	case ModeMarkdownV2:
+		text, entities, err = ParseMarkdownV2(text)
+	case ModeMarkdown:
+		err = ErrSplitMarkdown
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Formatted{Text: text, Entities: entities}, nil
+}
+
+func (b *Bot) parseMode(opt *SendOptions) ParseMode {
+	if opt.ParseMode != ModeDefault {
+		return opt.ParseMode
+	}
+	return b.handler.parseMode
+}
+
+func captionOf(what interface{}) (string, bool) {
+	switch m := what.(type) {
+	case *Photo:
+		return m.Caption, true
+	case *Audio:
+		return m.Caption, true
+	case *Document:
+		return m.Caption, true
+	case *Video:
+		return m.Caption, true
+	case *Animation:
+		return m.Caption, true
+	case *Voice:
+		return m.Caption, true
+	default:
+		return "", false
+	}
+}
+
+// withCaption returns a shallow copy of the media with the caption.
+func withCaption(media Sendable, caption string) Sendable {
+	switch m := media.(type) {
+	case *Photo:
+		cp := *m
+		cp.Caption = caption
+		return &cp
+	case *Audio:
+		cp := *m
+		cp.Caption = caption
+		return &cp
+	case *Document:
+		cp := *m
+		cp.Caption = caption
+		return &cp
+	case *Video:
+		cp := *m
+		cp.Caption = caption
+		return &cp
+	case *Animation:
+		cp := *m
+		cp.Caption = caption
+		return &cp
+	case *Voice:
+		cp := *m
+		cp.Caption = caption
+		return &cp
+	default:
+		return media
+	}
+}
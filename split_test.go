@@ -0,0 +1,122 @@
+package telebot
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormattedSplit(t *testing.T) {
+	f := Format("aaaa bbbb\n", Bold("cccc dddd"), "\n\neeee")
+
+	parts := f.Split(12)
+	require.Len(t, parts, 3)
+	assert.Equal(t, "aaaa bbbb", parts[0].Text)
+	assert.Empty(t, parts[0].Entities)
+	assert.Equal(t, "cccc dddd", parts[1].Text)
+	assert.Equal(t, Entities{{Type: EntityBold, Offset: 0, Length: 9}}, parts[1].Entities)
+	assert.Equal(t, "eeee", parts[2].Text)
+
+	// Entities crossing the cut are continued.
+	parts = Format(Italic("aaaa bbbb cccc")).Split(10)
+	require.Len(t, parts, 2)
+	assert.Equal(t, Entities{{Type: EntityItalic, Offset: 0, Length: 9}}, parts[0].Entities)
+	assert.Equal(t, Entities{{Type: EntityItalic, Offset: 0, Length: 4}}, parts[1].Entities)
+
+	// Neither atomic entities nor surrogate pairs are cut.
+	parts = Format("aaa", CustomEmoji("1", "👍"), "🎉🎉").Split(4)
+	require.Len(t, parts, 3)
+	assert.Equal(t, []string{"aaa", "👍🎉", "🎉"}, []string{parts[0].Text, parts[1].Text, parts[2].Text})
+	assert.Equal(t, Entities{{Type: EntityCustomEmoji, Offset: 0, Length: 2, CustomEmoji: "1"}}, parts[1].Entities)
+
+	// Separators inside atomic entities are skipped...
+	user := &User{ID: 1}
+	parts = Format("hello ", Mention(user, "John Smith"), " bye").Split(12)
+	require.Len(t, parts, 3)
+	assert.Equal(t, []string{"hello", "John Smith", "bye"}, []string{parts[0].Text, parts[1].Text, parts[2].Text})
+	assert.Empty(t, parts[0].Entities)
+	assert.Equal(t, Entities{{Type: EntityTMention, Offset: 0, Length: 10, User: user}}, parts[1].Entities)
+	assert.Empty(t, parts[2].Entities)
+
+	// ...unless the entity doesn't fit into the limit itself.
+	parts = Format(Code("aaaa bbbb cccc")).Split(10)
+	require.Len(t, parts, 2)
+	assert.Equal(t, Entities{{Type: EntityCode, Offset: 0, Length: 9}}, parts[0].Entities)
+	assert.Equal(t, Entities{{Type: EntityCode, Offset: 0, Length: 4}}, parts[1].Entities)
+
+	parts = Format("short").Split(MaxMessageLength)
+	require.Len(t, parts, 1)
+	assert.Equal(t, "short", parts[0].Text)
+}
+
+func TestBotSendSplit(t *testing.T) {
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		if method == "sendPhoto" {
+			return `{"ok":true,"result":{"message_id":1,"chat":{"id":1},"photo":[{"file_id":"photo"}]}}`
+		}
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	b, err := NewBot(Settings{
+		URL:     api.URL(),
+		Offline: true,
+		Handler: NewHandler(HandlerSettings{ParseMode: ModeHTML}),
+	})
+	require.NoError(t, err)
+
+	markup := &ReplyMarkup{InlineKeyboard: [][]InlineButton{{{Text: "ok", Data: "ok"}}}}
+	long := "<b>" + strings.Repeat("word ", 1000) + "</b>"
+
+	msgs, err := b.SendSplit(&Chat{ID: 1}, long, markup, &ReplyParams{MessageID: 7})
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	requests := api.Requests()
+	require.Len(t, requests, 2)
+
+	for i, req := range requests {
+		params := req.Params
+		assert.Equal(t, "sendMessage", req.Method)
+		assert.Empty(t, params["parse_mode"])
+		assert.Contains(t, params["entities"], `"bold"`)
+		assert.NotContains(t, params["text"], "<b>")
+		assert.LessOrEqual(t, utf16Len(params["text"]), MaxMessageLength)
+		assert.Equal(t, i == 1, params["reply_markup"] != "")
+	}
+
+	api.Reset()
+	caption := strings.Repeat("a&lt;b ", 300)
+	photo := &Photo{File: File{FileID: "photo"}, Caption: caption}
+
+	msgs, err = b.SendSplit(&Chat{ID: 1}, photo, markup)
+	require.NoError(t, err)
+	assert.Equal(t, caption, photo.Caption)
+	require.Len(t, msgs, 2)
+	requests = api.Requests()
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, "sendPhoto", requests[0].Method)
+	assert.Equal(t, ModeHTML, requests[0].Params["parse_mode"])
+	assert.Empty(t, requests[0].Params["reply_markup"])
+	assert.Equal(t, "sendMessage", requests[1].Method)
+	assert.True(t, strings.HasPrefix(requests[1].Params["text"], "a<b"))
+	assert.NotEmpty(t, requests[1].Params["reply_markup"])
+
+	// The same media can be sent from several goroutines.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.SendSplit(&Chat{ID: 1}, photo)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, caption, photo.Caption)
+
+	_, err = b.SendSplit(&Chat{ID: 1}, "*a*", ModeMarkdown)
+	assert.ErrorIs(t, err, ErrSplitMarkdown)
+}
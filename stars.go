@@ -0,0 +1,156 @@
+package telebot
+
+import (
+	"strconv"
+	"time"
+)
+
+// MaxStarTransactions is the maximum number of transactions
+// returned by a single StarTransactions call.
+const MaxStarTransactions = 100
+
+// StarTransaction describes a Telegram Star transaction.
+type StarTransaction struct {
+	// Unique identifier of the transaction. Coincides with the identifier
+	// of the original transaction for refund transactions.
+	ID string `json:"id"`
+
+	// Number of Telegram Stars transferred by the transaction.
+	Amount int `json:"amount"`
+
+	// (Optional) The number of 1/1000000000 shares of Telegram Stars
+	// transferred by the transaction.
+	NanostarAmount int `json:"nanostar_amount"`
+
+	// Date the transaction was created in Unix time,
+	// use Time() to get time.Time.
+	Unixtime int64 `json:"date"`
+
+	// (Optional) Source of an incoming transaction.
+	Source *TransactionPartner `json:"source"`
+
+	// (Optional) Receiver of an outgoing transaction.
+	Receiver *TransactionPartner `json:"receiver"`
+}
+
+// Time returns the moment of the transaction in local time.
+func (t *StarTransaction) Time() time.Time {
+	return time.Unix(t.Unixtime, 0)
+}
+
+// TransactionPartnerType describes a type of the transaction partner.
+type TransactionPartnerType = string
+
+const (
+	PartnerUser        TransactionPartnerType = "user"
+	PartnerChat        TransactionPartnerType = "chat"
+	PartnerAffiliate   TransactionPartnerType = "affiliate_program"
+	PartnerFragment    TransactionPartnerType = "fragment"
+	PartnerTelegramAds TransactionPartnerType = "telegram_ads"
+	PartnerTelegramAPI TransactionPartnerType = "telegram_api"
+	PartnerOther       TransactionPartnerType = "other"
+)
+
+// TransactionPartner describes the source or the receiver of a transaction.
+type TransactionPartner struct {
+	Type TransactionPartnerType `json:"type"`
+
+	// (Optional) Information about the user or the chat.
+	User *User `json:"user"`
+	Chat *Chat `json:"chat"`
+
+	// (Optional) Bot-specified invoice payload, for the user payments.
+	Payload string `json:"invoice_payload"`
+
+	// (Optional) The duration of the paid subscription, for the user payments.
+	SubscriptionPeriod int `json:"subscription_period"`
+
+	// (Optional) Bot-specified paid media payload, for the user payments.
+	PaidMediaPayload string `json:"paid_media_payload"`
+
+	// (Optional) State of the withdrawal, for the Fragment transactions.
+	Withdrawal *RevenueWithdrawal `json:"withdrawal_state"`
+
+	// (Optional) The number of successful requests that exceeded regular
+	// limits and were therefore billed, for the Telegram API transactions.
+	RequestCount int `json:"request_count"`
+}
+
+// RevenueWithdrawal describes the state of a revenue withdrawal operation.
+type RevenueWithdrawal struct {
+	// Type of the state: "pending", "succeeded" or "failed".
+	Type string `json:"type"`
+
+	// (Optional) Date the withdrawal was completed in Unix time.
+	Unixtime int64 `json:"date"`
+
+	// (Optional) An HTTPS URL that can be used to see transaction details.
+	URL string `json:"url"`
+}
+
+// RefundStarPayment refunds a successful payment in Telegram Stars.
+func (b *Bot) RefundStarPayment(user Recipient, chargeID string) error {
+	params := map[string]string{
+		"user_id":                    user.Recipient(),
+		"telegram_payment_charge_id": chargeID,
+	}
+
+	_, err := b.Raw("refundStarPayment", params)
+	return err
+}
+
+// EditStarSubscription cancels or re-enables extension
+// of a subscription paid in Telegram Stars.
+func (b *Bot) EditStarSubscription(user Recipient, chargeID string, cancel bool) error {
+	params := map[string]string{
+		"user_id":                    user.Recipient(),
+		"telegram_payment_charge_id": chargeID,
+		"is_canceled":                strconv.FormatBool(cancel),
+	}
+
+	_, err := b.Raw("editUserStarSubscription", params)
+	return err
+}
+
+// StarTransactions returns the bot's Telegram Star transactions in
+// chronological order, skipping the first offset ones. The limit
+// defaults to MaxStarTransactions.
+func (b *Bot) StarTransactions(offset, limit int) ([]StarTransaction, error) {
+	params := map[string]string{
+		"offset": strconv.Itoa(offset),
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+
+	var resp struct {
+		Transactions []StarTransaction `json:"transactions"`
+	}
+	if err := b.Call("getStarTransactions", params, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Transactions, nil
+}
+
+// EachStarTransaction pages through the bot's Telegram Star transactions
+// starting from the offset and calls fn on each of them. It stops on
+// the first error returned by fn or by the API.
+func (b *Bot) EachStarTransaction(offset int, fn func(StarTransaction) error) error {
+	for {
+		txs, err := b.StarTransactions(offset, MaxStarTransactions)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+
+		if len(txs) < MaxStarTransactions {
+			return nil
+		}
+		offset += len(txs)
+	}
+}
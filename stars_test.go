@@ -0,0 +1,128 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStars(t *testing.T) {
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		switch method {
+		case "sendInvoice":
+			return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+		case "createInvoiceLink":
+			return `{"ok":true,"result":"https://t.me/$abc"}`
+		case "getStarTransactions":
+			offset, _ := strconv.Atoi(params["offset"])
+			var txs []string
+			for i := offset; i < 250 && i < offset+MaxStarTransactions; i++ {
+				txs = append(txs, fmt.Sprintf(`{"id":"%d","amount":1,"date":1}`, i))
+			}
+			return fmt.Sprintf(`{"ok":true,"result":{"transactions":[%s]}}`, strings.Join(txs, ","))
+		}
+		return ""
+	})
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	invoice := StarsInvoice("Pro", "Pro features", "pro", 50)
+	_, err = b.Send(&Chat{ID: 1}, &invoice)
+	require.NoError(t, err)
+	req := api.Last()
+	assert.Equal(t, "sendInvoice", req.Method)
+	assert.Equal(t, Stars, req.Params["currency"])
+	assert.Equal(t, "", req.Params["provider_token"])
+	assert.Equal(t, `[{"label":"Pro","amount":50}]`, req.Params["prices"])
+	assert.NotContains(t, req.Params, "subscription_period")
+
+	link, err := b.CreateInvoiceLink(StarsSubscription("Pro", "Pro features", "pro", 50))
+	require.NoError(t, err)
+	assert.Equal(t, "https://t.me/$abc", link)
+	req = api.Last()
+	assert.Equal(t, "2592000", req.Params["subscription_period"])
+
+	api.Reset()
+	for _, i := range []Invoice{
+		{Currency: Stars, Token: "token", Prices: []Price{{Amount: 1}}},
+		{Currency: Stars},
+		{Currency: Stars, Prices: []Price{{Amount: 1}}, MaxTipAmount: 10},
+		{Currency: Stars, Prices: []Price{{Amount: 1}}, NeedShippingAddress: true},
+		{Currency: Stars, Prices: []Price{{Amount: 1}}, SubscriptionPeriod: 60},
+		{Currency: "USD", Prices: []Price{{Amount: 1}}, SubscriptionPeriod: StarsSubscriptionPeriod},
+	} {
+		_, err := b.CreateInvoiceLink(i)
+		assert.ErrorIs(t, err, ErrBadStarsInvoice)
+	}
+	_, err = b.Send(&Chat{ID: 1}, &Invoice{Currency: Stars, Prices: []Price{{Amount: 1}}, SubscriptionPeriod: StarsSubscriptionPeriod})
+	assert.ErrorIs(t, err, ErrBadStarsInvoice)
+	assert.Empty(t, api.Requests())
+
+	require.NoError(t, b.RefundStarPayment(&User{ID: 1}, "charge"))
+	req = api.Last()
+	assert.Equal(t, "refundStarPayment", req.Method)
+	assert.Equal(t, map[string]string{"user_id": "1", "telegram_payment_charge_id": "charge"}, req.Params)
+
+	require.NoError(t, b.EditStarSubscription(&User{ID: 1}, "charge", true))
+	req = api.Last()
+	assert.Equal(t, "editUserStarSubscription", req.Method)
+	assert.Equal(t, "true", req.Params["is_canceled"])
+
+	txs, err := b.StarTransactions(10, 5)
+	require.NoError(t, err)
+	req = api.Last()
+	assert.Equal(t, map[string]string{"offset": "10", "limit": "5"}, req.Params)
+	assert.Equal(t, "10", txs[0].ID)
+
+	var ids []string
+	err = b.EachStarTransaction(50, func(tx StarTransaction) error {
+		ids = append(ids, tx.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, ids, 200)
+	assert.Equal(t, "50", ids[0])
+	assert.Equal(t, "249", ids[199])
+	req = api.Last()
+	assert.Equal(t, "250", req.Params["offset"])
+}
+
+func TestPaymentUpdates(t *testing.T) {
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"message_id": 1,
+		"successful_payment": {
+			"currency": "XTR",
+			"total_amount": 50,
+			"subscription_expiration_date": 1700000000,
+			"is_recurring": true,
+			"is_first_recurring": true
+		}
+	}`), &msg))
+
+	require.NotNil(t, msg.Payment)
+	assert.True(t, msg.Payment.Recurring)
+	assert.True(t, msg.Payment.FirstRecurring)
+	assert.Equal(t, int64(1700000000), msg.Payment.SubscriptionExpirationDate().Unix())
+
+	var refunded bool
+	h := NewHandler(HandlerSettings{Synchronous: true})
+	b, err := NewBot(Settings{Offline: true, Handler: h})
+	require.NoError(t, err)
+	h.Handle(OnRefund, func(c Context) error {
+		refunded = c.Message().RefundedPayment.TelegramChargeID == "charge"
+		return nil
+	})
+
+	b.ProcessUpdate(Update{Message: &Message{
+		Chat:            &Chat{ID: 1},
+		RefundedPayment: &RefundedPayment{Currency: Stars, TelegramChargeID: "charge"},
+	}})
+	assert.True(t, refunded)
+}
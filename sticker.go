@@ -76,18 +76,11 @@ func (b *Bot) UploadSticker(to Recipient, format StickerSetFormat, f File) (*Fil
 		"sticker_format": format,
 	}
 
-	data, err := b.sendFiles("uploadStickerFile", map[string]File{"0": f}, params)
-	if err != nil {
+	var file File
+	if err := b.CallFiles("uploadStickerFile", params, map[string]File{"0": f}, &file); err != nil {
 		return nil, err
 	}
-
-	var resp struct {
-		Result File
-	}
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, wrapError(err)
-	}
-	return &resp.Result, nil
+	return &file, nil
 }
 
 // StickerSet returns a sticker set on success.
@@ -158,6 +151,29 @@ func (b *Bot) AddStickerToSet(of Recipient, name string, sticker InputSticker) e
 	return err
 }
 
+// ReplaceSticker replaces the existing sticker in the sticker set with
+// a new one. It's equivalent to deleting the old sticker, then adding
+// the new one at its position.
+func (b *Bot) ReplaceSticker(of Recipient, name, old string, sticker InputSticker) error {
+	files := make(map[string]File)
+	repr := sticker.File.process("0", files)
+	if repr == "" {
+		return errors.New("telebot: sticker does not exist")
+	}
+
+	sticker.Sticker = repr
+	data, _ := json.Marshal(sticker)
+
+	params := map[string]string{
+		"user_id":     of.Recipient(),
+		"name":        name,
+		"old_sticker": old,
+		"sticker":     string(data),
+	}
+
+	return b.CallFiles("replaceStickerInSet", params, files, nil)
+}
+
 // SetStickerPosition moves a sticker in set to a specific position.
 func (b *Bot) SetStickerPosition(sticker string, position int) error {
 	params := map[string]string{
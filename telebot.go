@@ -34,6 +34,7 @@ var (
 	ErrCouldNotUpdate  = errors.New("telebot: could not fetch new updates")
 	ErrTrueResult      = errors.New("telebot: result is True")
 	ErrBadContext      = errors.New("telebot: context does not contain message")
+	ErrNoInlineMessage = errors.New("telebot: inline result has no message, attach reply markup to the result")
 )
 
 const DefaultApiURL = "https://api.telegram.org"
@@ -60,6 +61,7 @@ const (
 	OnDice                 = "\adice"
 	OnInvoice              = "\ainvoice"
 	OnPayment              = "\apayment"
+	OnRefund               = "\arefund"
 	OnGame                 = "\agame"
 	OnPoll                 = "\apoll"
 	OnPollAnswer           = "\apoll_answer"
@@ -93,6 +95,7 @@ const (
 	OnMigration = "\amigration"
 
 	OnMedia           = "\amedia"
+	OnAlbum           = "\aalbum"
 	OnCallback        = "\acallback"
 	OnQuery           = "\aquery"
 	OnInlineResult    = "\ainline_result"
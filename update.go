@@ -58,6 +58,9 @@ func (b *Bot) ProcessUpdate(u Update) {
 				}
 
 				m.Payload = match[0][5]
+				if command == "/start" && m.Payload != "" && b.handleStartPayload(m.Payload, c) {
+					return
+				}
 				if b.handle(command, c) {
 					return
 				}
@@ -72,6 +75,9 @@ func (b *Bot) ProcessUpdate(u Update) {
 			return
 		}
 
+		if b.handleAlbum(c) {
+			return
+		}
 		if b.handleMedia(c) {
 			return
 		}
@@ -104,6 +110,10 @@ func (b *Bot) ProcessUpdate(u Update) {
 			b.handle(OnPayment, c)
 			return
 		}
+		if m.RefundedPayment != nil {
+			b.handle(OnRefund, c)
+			return
+		}
 
 		if m.TopicCreated != nil {
 			b.handle(OnTopicCreated, c)
@@ -243,6 +253,10 @@ func (b *Bot) ProcessUpdate(u Update) {
 			return
 		}
 
+		if b.handleAlbum(c) {
+			return
+		}
+
 		b.handle(OnChannelPost, c)
 		return
 	}
@@ -260,7 +274,7 @@ func (b *Bot) ProcessUpdate(u Update) {
 				if handler, ok := b.handler.handlers["\f"+unique]; ok {
 					u.Callback.Unique = unique
 					u.Callback.Data = payload
-					b.runHandler(handler, c)
+					b.runHandler(handler, withEndpoint(c, "\f"+unique))
 					return
 				}
 			}
@@ -328,12 +342,21 @@ func (b *Bot) ProcessUpdate(u Update) {
 
 func (b *Bot) handle(end string, c Context) bool {
 	if handler, ok := b.handler.handlers[end]; ok {
-		b.runHandler(handler, c)
+		b.runHandler(handler, withEndpoint(c, end))
 		return true
 	}
 	return false
 }
 
+// withEndpoint remembers the endpoint in the native context,
+// so it's available for the middleware via Context.Endpoint.
+func withEndpoint(c Context, end string) Context {
+	if nc, ok := c.(*nativeContext); ok {
+		nc.endpoint = end
+	}
+	return c
+}
+
 func (b *Bot) handleMedia(c Context) bool {
 	var (
 		m     = c.Message()
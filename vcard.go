@@ -0,0 +1,233 @@
+package telebot
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxVCardSize is the maximum size of the vCard in bytes.
+const MaxVCardSize = 2048
+
+var (
+	// ErrBadVCard is returned by ParseVCard when the text isn't a vCard.
+	ErrBadVCard = errors.New("telebot: malformed vcard")
+
+	// ErrTooLongVCard is returned when the contact's vCard
+	// exceeds MaxVCardSize.
+	ErrTooLongVCard = errors.New("telebot: vcard exceeds 2048 bytes")
+)
+
+// VCard is a subset of the vCard 3.0 fields, which are displayed by
+// Telegram clients. Use its String to fill Contact.VCard or
+// ContactResult.VCard, and ParseVCard to read the received ones.
+//
+// Example:
+//
+//	card := &tele.VCard{
+//		FirstName: "John",
+//		LastName:  "Doe",
+//		Phones:    []tele.VCardValue{{Type: "CELL", Value: "+123456789"}},
+//		Emails:    []tele.VCardValue{{Value: "john@example.com"}},
+//		Org:       "Example Inc.",
+//	}
+//	c.Send(card.Contact())
+type VCard struct {
+	FirstName string
+	LastName  string
+	Org       string
+	Title     string
+	Phones    []VCardValue
+	Emails    []VCardValue
+	Addresses []VCardAddress
+}
+
+// VCardValue is a typed value of the vCard, like a phone or an email.
+// Type is an optional vCard type, e.g. "CELL", "WORK" or "HOME".
+type VCardValue struct {
+	Type  string
+	Value string
+}
+
+// VCardAddress is a postal address of the vCard.
+type VCardAddress struct {
+	Type       string
+	Street     string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+}
+
+var (
+	vcardEscaper   = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+	vcardUnescaper = strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, "\n", `\N`, "\n")
+)
+
+// String returns the vCard text.
+func (v *VCard) String() string {
+	var sb strings.Builder
+
+	line := func(name, typ string, values ...string) {
+		sb.WriteString(name)
+		if typ != "" {
+			sb.WriteString(";TYPE=" + typ)
+		}
+		sb.WriteByte(':')
+		for i, value := range values {
+			if i > 0 {
+				sb.WriteByte(';')
+			}
+			sb.WriteString(vcardEscaper.Replace(value))
+		}
+		sb.WriteString("\r\n")
+	}
+
+	sb.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+	line("N", "", v.LastName, v.FirstName, "", "", "")
+	line("FN", "", strings.TrimSpace(v.FirstName+" "+v.LastName))
+	if v.Org != "" {
+		line("ORG", "", v.Org)
+	}
+	if v.Title != "" {
+		line("TITLE", "", v.Title)
+	}
+	for _, phone := range v.Phones {
+		line("TEL", phone.Type, phone.Value)
+	}
+	for _, email := range v.Emails {
+		line("EMAIL", email.Type, email.Value)
+	}
+	for _, a := range v.Addresses {
+		line("ADR", a.Type, "", "", a.Street, a.City, a.Region, a.PostalCode, a.Country)
+	}
+	sb.WriteString("END:VCARD")
+
+	return sb.String()
+}
+
+// Contact returns the contact with the first phone of the vCard.
+func (v *VCard) Contact() *Contact {
+	c := &Contact{
+		FirstName: v.FirstName,
+		LastName:  v.LastName,
+		VCard:     v.String(),
+	}
+	if len(v.Phones) > 0 {
+		c.PhoneNumber = v.Phones[0].Value
+	}
+	return c
+}
+
+// ParseVCard parses the vCard text, ignoring the unsupported fields.
+func ParseVCard(s string) (*VCard, error) {
+	// Unfold the lines continued with a space or a tab.
+	s = strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(s)
+
+	var (
+		v     VCard
+		begin bool
+		fn    string
+	)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			continue
+		}
+
+		params := strings.Split(line[:i], ";")
+		name := strings.ToUpper(params[0])
+		if j := strings.LastIndexByte(name, '.'); j >= 0 {
+			name = name[j+1:] // item1.TEL
+		}
+		values := splitVCardValue(line[i+1:])
+
+		var types []string
+		for _, param := range params[1:] {
+			param = strings.ToUpper(param)
+			if strings.HasPrefix(param, "TYPE=") {
+				param = param[len("TYPE="):]
+			} else if strings.Contains(param, "=") {
+				continue
+			}
+			for _, t := range strings.Split(param, ",") {
+				if t != "PREF" && t != "VOICE" && t != "INTERNET" {
+					types = append(types, t)
+				}
+			}
+		}
+		typ := strings.Join(types, ",")
+
+		switch name {
+		case "BEGIN":
+			begin = strings.EqualFold(values[0], "VCARD")
+		case "N":
+			v.LastName = values[0]
+			if len(values) > 1 {
+				v.FirstName = values[1]
+			}
+		case "FN":
+			fn = values[0]
+		case "ORG":
+			v.Org = strings.Join(values, " ")
+		case "TITLE":
+			v.Title = values[0]
+		case "TEL":
+			v.Phones = append(v.Phones, VCardValue{Type: typ, Value: values[0]})
+		case "EMAIL":
+			v.Emails = append(v.Emails, VCardValue{Type: typ, Value: values[0]})
+		case "ADR":
+			values = append(values, make([]string, 7)...)
+			v.Addresses = append(v.Addresses, VCardAddress{
+				Type:       typ,
+				Street:     values[2],
+				City:       values[3],
+				Region:     values[4],
+				PostalCode: values[5],
+				Country:    values[6],
+			})
+		}
+	}
+
+	if !begin {
+		return nil, ErrBadVCard
+	}
+	if v.FirstName == "" && v.LastName == "" {
+		v.FirstName = fn
+	}
+
+	return &v, nil
+}
+
+// splitVCardValue splits the value by the unescaped semicolons.
+func splitVCardValue(s string) []string {
+	var (
+		values []string
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ';':
+			values = append(values, vcardUnescaper.Replace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, vcardUnescaper.Replace(s[start:]))
+}
+
+// Card parses the vCard of the contact. If the contact has no vCard,
+// it's built from the contact's name and phone number.
+func (c *Contact) Card() (*VCard, error) {
+	if c.VCard == "" {
+		return &VCard{
+			FirstName: c.FirstName,
+			LastName:  c.LastName,
+			Phones:    []VCardValue{{Value: c.PhoneNumber}},
+		}, nil
+	}
+	return ParseVCard(c.VCard)
+}
@@ -0,0 +1,81 @@
+package telebot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVCard(t *testing.T) {
+	card := &VCard{
+		FirstName: "John",
+		LastName:  "Doe",
+		Org:       "Acme; Inc.",
+		Phones:    []VCardValue{{Type: "CELL", Value: "+123"}, {Type: "WORK", Value: "+456"}},
+		Emails:    []VCardValue{{Value: "john@example.com"}},
+		Addresses: []VCardAddress{{Type: "HOME", Street: "Main St, 1", City: "Kyiv", Country: "Ukraine"}},
+	}
+
+	text := card.String()
+	assert.Contains(t, text, "N:Doe;John;;;\r\n")
+	assert.Contains(t, text, "ORG:Acme\\; Inc.\r\n")
+	assert.Contains(t, text, "ADR;TYPE=HOME:;;Main St\\, 1;Kyiv;;;Ukraine\r\n")
+
+	parsed, err := ParseVCard(text)
+	require.NoError(t, err)
+	assert.Equal(t, card, parsed)
+
+	c := card.Contact()
+	assert.Equal(t, "+123", c.PhoneNumber)
+	assert.Equal(t, "John", c.FirstName)
+
+	parsed, err = c.Card()
+	require.NoError(t, err)
+	assert.Equal(t, card, parsed)
+
+	parsed, err = ParseVCard("BEGIN:VCARD\nVERSION:2.1\nFN:Jane\nitem1.TEL;CELL;PREF:+78\n" +
+		"EMAIL;TYPE=INTERNET,WORK:jane@exa\n mple.com\nEND:VCARD")
+	require.NoError(t, err)
+	assert.Equal(t, &VCard{
+		FirstName: "Jane",
+		Phones:    []VCardValue{{Type: "CELL", Value: "+78"}},
+		Emails:    []VCardValue{{Type: "WORK", Value: "jane@example.com"}},
+	}, parsed)
+
+	_, err = ParseVCard("not a vcard")
+	assert.ErrorIs(t, err, ErrBadVCard)
+
+	parsed, err = (&Contact{PhoneNumber: "+1", FirstName: "A"}).Card()
+	require.NoError(t, err)
+	assert.Equal(t, &VCard{FirstName: "A", Phones: []VCardValue{{Value: "+1"}}}, parsed)
+}
+
+func TestContactSend(t *testing.T) {
+	api := newTestAPI(t, func(method string, params map[string]string) string {
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1},"contact":{"phone_number":"+1","first_name":"A"}}}`
+	})
+
+	b, err := NewBot(Settings{URL: api.URL(), Offline: true})
+	require.NoError(t, err)
+
+	card := &VCard{FirstName: "A", Phones: []VCardValue{{Value: "+1"}}}
+	msg, err := b.Send(&Chat{ID: 1}, card.Contact(), Silent)
+	require.NoError(t, err)
+
+	params := api.Last().Params
+	assert.Equal(t, "+1", params["phone_number"])
+	assert.Equal(t, card.String(), params["vcard"])
+	assert.Equal(t, "true", params["disable_notification"])
+
+	c := b.NewContext(Update{Message: msg})
+	require.NotNil(t, c.Contact())
+	assert.Equal(t, "+1", c.Contact().PhoneNumber)
+
+	api.Reset()
+	card.Org = strings.Repeat("x", MaxVCardSize)
+	_, err = b.Send(&Chat{ID: 1}, card.Contact())
+	assert.ErrorIs(t, err, ErrTooLongVCard)
+	assert.Empty(t, api.Requests())
+}